@@ -0,0 +1,192 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/Nydauron/avocado2sciolyff/batch"
+	"github.com/Nydauron/avocado2sciolyff/catalog"
+	"github.com/Nydauron/avocado2sciolyff/metadata"
+	"github.com/Nydauron/avocado2sciolyff/output"
+	"github.com/Nydauron/avocado2sciolyff/prompts"
+	"github.com/Nydauron/avocado2sciolyff/sciolyff"
+	"github.com/Nydauron/avocado2sciolyff/writers"
+	"github.com/urfave/cli/v2"
+)
+
+const jobsFlag = "jobs"
+
+func batchFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.IntFlag{
+			Name:    jobsFlag,
+			Aliases: []string{"j"},
+			Usage:   "Number of tournaments converted at the same time",
+			Value:   4,
+		},
+		&cli.StringFlag{
+			Name:  formatFlag,
+			Usage: "Output format of every tournament. Defaults to the format matching each output file extension, or YAML",
+		},
+		&cli.StringFlag{
+			Name:  eventCatalogFlag,
+			Usage: "Path to a YAML event catalog listing the official events of each rules year and division, used instead of the built-in one",
+		},
+		&cli.BoolFlag{
+			Name:  noValidateFlag,
+			Usage: "Write the results even if they break the sciolyff rules",
+		},
+		&cli.BoolFlag{
+			Name:  noClobberFlag,
+			Usage: "Refuse to overwrite existing output files",
+		},
+		&cli.BoolFlag{
+			Name:  forceFlag,
+			Usage: "Overwrite output files even with --" + noClobberFlag + " or when they hold a different tournament",
+		},
+	}
+}
+
+// Converts every tournament in the manifest given as an argument, a few at a
+// time and without prompting, then lists which ones failed
+func batchHandle(cCtx *cli.Context) error {
+	if cCtx.NArg() != 1 {
+		return fmt.Errorf("expected a manifest, got %d arguments", cCtx.NArg())
+	}
+	entries, err := batch.Load(cCtx.Args().First())
+	if err != nil {
+		return err
+	}
+	jobCount := cCtx.Int(jobsFlag)
+	if jobCount < 1 {
+		return fmt.Errorf("--%s must be at least 1", jobsFlag)
+	}
+	var format *output.Format
+	if cCtx.IsSet(formatFlag) {
+		parsedFormat, err := output.ParseFormat(cCtx.String(formatFlag))
+		if err != nil {
+			return fmt.Errorf("--%s: %w", formatFlag, err)
+		}
+		format = &parsedFormat
+	}
+	// Only ever read, so it is shared by every conversion
+	eventCatalog := catalog.Default()
+	if catalogLocation := cCtx.String(eventCatalogFlag); catalogLocation != "" {
+		if eventCatalog, err = catalog.Load(catalogLocation); err != nil {
+			return err
+		}
+	}
+
+	// Tournaments in the same output directory can be given the same file
+	// name, so each file is only written by one conversion at a time. The
+	// collision check then sees the results written before it.
+	outputLocks := pathLocks{}
+	convertEntry := func(e batch.Entry) error {
+		if e.Output == stdoutCLIName {
+			return fmt.Errorf("results cannot be written to stdout in a batch")
+		}
+		// Tournaments are converted side by side, so every line they log is
+		// marked with the tournament it belongs to
+		logWriter := writers.NewPrefixWriter(os.Stderr, "["+e.Name+"] ")
+		defer logWriter.Flush()
+		opts := sciolyff.Options{PlacingOrder: sciolyff.PlacingOrderEvent, Catalog: eventCatalog, Log: logWriter}
+		if e.Metadata != "" {
+			metadataFile, err := metadata.Load(e.Metadata)
+			if err != nil {
+				return err
+			}
+			if err := metadataFile.Apply(&opts); err != nil {
+				return fmt.Errorf("invalid metadata file %s: %w", e.Metadata, err)
+			}
+		}
+		// Manifests often point into a tree of directories that does not
+		// exist yet
+		if err := os.MkdirAll(filepath.Dir(e.Output), 0755); err != nil {
+			return err
+		}
+		entryFormat := output.FormatForPath(e.Output)
+		if format != nil {
+			entryFormat = *format
+		}
+		openOutput, err := newOutputOpener(e.Output, entryFormat, cCtx.Bool(noClobberFlag), cCtx.Bool(forceFlag), logWriter, &outputLocks)
+		if err != nil {
+			return err
+		}
+		return convert(conversion{
+			inputLocation:        e.Overall,
+			inputByGroupLocation: e.Groups,
+			isCSVFile:            e.IsCSV,
+			openOutput:           openOutput,
+			format:               entryFormat,
+			shouldValidate:       !cCtx.Bool(noValidateFlag),
+			prompter:             prompts.DefaultsPrompter{},
+			opts:                 opts,
+		})
+	}
+
+	errs := make([]error, len(entries))
+	jobs := make(chan int)
+	wg := sync.WaitGroup{}
+	for range min(jobCount, len(entries)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				errs[i] = convertEntry(entries[i])
+			}
+		}()
+	}
+	for i := range entries {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	failureCount := 0
+	fmt.Println()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for i, e := range entries {
+		if errs[i] != nil {
+			failureCount++
+			fmt.Fprintf(w, "FAIL\t%s\t%v\n", e.Name, errs[i])
+		} else {
+			fmt.Fprintf(w, "OK\t%s\t\n", e.Name)
+		}
+	}
+	w.Flush()
+	if failureCount > 0 {
+		return cli.Exit(fmt.Sprintf("%d of %d tournaments failed to convert", failureCount, len(entries)), 1)
+	}
+	fmt.Printf("Converted all %d tournaments\n", len(entries))
+	return nil
+}
+
+// Locks on the files results are written to, by absolute path
+type pathLocks struct {
+	mu    sync.Mutex
+	locks map[string]*sync.Mutex
+}
+
+// Locks the file at path, waiting for any other conversion writing it to
+// finish. The returned function releases the lock, and can be called more than
+// once.
+func (l *pathLocks) lock(path string) func() {
+	if absolutePath, err := filepath.Abs(path); err == nil {
+		path = absolutePath
+	}
+	l.mu.Lock()
+	if l.locks == nil {
+		l.locks = map[string]*sync.Mutex{}
+	}
+	lock, ok := l.locks[path]
+	if !ok {
+		lock = &sync.Mutex{}
+		l.locks[path] = lock
+	}
+	l.mu.Unlock()
+	lock.Lock()
+	return sync.OnceFunc(lock.Unlock)
+}
@@ -0,0 +1,144 @@
+package batch
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Nydauron/avocado2sciolyff/output"
+	"gopkg.in/yaml.v3"
+)
+
+// Entry is a tournament to convert. Paths relative to the manifest are
+// resolved against the manifest's directory.
+type Entry struct {
+	// Shown in the summary. Defaults to the output path.
+	Name string `yaml:"name,omitempty"`
+	// URL or path of the overall results table
+	Overall string `yaml:"overall"`
+	// URL or path of the results table by group/track, if any
+	Groups string `yaml:"groups,omitempty"`
+	// Whether the tables are CSV files rather than HTML pages
+	IsCSV bool `yaml:"csv,omitempty"`
+	// Path of the metadata file answering the prompts, if any
+	Metadata string `yaml:"metadata,omitempty"`
+	// File or directory to write the results to
+	Output string `yaml:"output"`
+}
+
+// Columns of a CSV manifest, matching the YAML keys
+var csvColumns = []string{"name", "overall", "groups", "csv", "metadata", "output"}
+
+// Reads a manifest listing the tournaments to convert. Files ending in ".csv"
+// are read as CSV with a header row naming the columns, everything else as a
+// YAML list.
+func Load(path string) ([]Entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	entries := []Entry{}
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		entries, err = parseCSV(f)
+	} else {
+		err = yaml.NewDecoder(f).Decode(&entries)
+		if errors.Is(err, io.EOF) {
+			err = nil
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read manifest %s: %w", path, err)
+	}
+
+	dir := filepath.Dir(path)
+	// Tournament numbers by output file. Output directories are left out, as
+	// the file written in them is only known once the tournament is converted.
+	outputFiles := map[string]int{}
+	for i := range entries {
+		e := &entries[i]
+		if e.Overall == "" || e.Output == "" {
+			return nil, fmt.Errorf("manifest %s: tournament %d needs both an overall table and an output", path, i+1)
+		}
+		if e.Name == "" {
+			e.Name = e.Output
+		}
+		for _, location := range []*string{&e.Overall, &e.Groups, &e.Metadata, &e.Output} {
+			*location = resolve(dir, *location)
+		}
+		if output.IsDirectory(e.Output) {
+			continue
+		}
+		outputFile := filepath.Clean(e.Output)
+		if other, ok := outputFiles[outputFile]; ok {
+			return nil, fmt.Errorf("manifest %s: tournaments %d and %d are both written to %s", path, other, i+1, outputFile)
+		}
+		outputFiles[outputFile] = i + 1
+	}
+	return entries, nil
+}
+
+func parseCSV(r io.Reader) ([]Entry, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil || len(records) == 0 {
+		return nil, err
+	}
+	header := records[0]
+	for _, column := range header {
+		if !slices.Contains(csvColumns, strings.TrimSpace(column)) {
+			return nil, fmt.Errorf("unknown column %q (expected some of %s)", column, strings.Join(csvColumns, ", "))
+		}
+	}
+
+	entries := []Entry{}
+	for i, record := range records[1:] {
+		e := Entry{}
+		for j, column := range header {
+			value := strings.TrimSpace(record[j])
+			switch strings.TrimSpace(column) {
+			case "name":
+				e.Name = value
+			case "overall":
+				e.Overall = value
+			case "groups":
+				e.Groups = value
+			case "csv":
+				if value != "" {
+					if e.IsCSV, err = strconv.ParseBool(value); err != nil {
+						return nil, fmt.Errorf("row %d: csv must be true or false", i+2)
+					}
+				}
+			case "metadata":
+				e.Metadata = value
+			case "output":
+				e.Output = value
+			}
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// Resolves a relative path against dir, leaving URLs and "-" (stdout) as is
+func resolve(dir string, location string) string {
+	if location == "" || location == "-" || filepath.IsAbs(location) {
+		return location
+	}
+	if u, err := url.ParseRequestURI(location); err == nil && u.Scheme != "" {
+		return location
+	}
+	resolved := filepath.Join(dir, location)
+	// Keep the trailing separator that marks an output directory
+	if strings.HasSuffix(location, "/") || strings.HasSuffix(location, string(filepath.Separator)) {
+		resolved += string(filepath.Separator)
+	}
+	return resolved
+}
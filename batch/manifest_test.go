@@ -0,0 +1,103 @@
+package batch_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Nydauron/avocado2sciolyff/batch"
+)
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	manifest := filepath.Join(dir, "season.yaml")
+	contents := `- name: Illinois State C
+  overall: https://app.avogadro.ws/il/uiuc-state-c/results/overall
+  metadata: metadata/il-state-c.yaml
+  output: results/
+- overall: pages/naperville.csv
+  csv: true
+  output: results/naperville.yaml
+`
+	if err := os.WriteFile(manifest, []byte(contents), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := batch.Load(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []batch.Entry{
+		{
+			Name:     "Illinois State C",
+			Overall:  "https://app.avogadro.ws/il/uiuc-state-c/results/overall",
+			Metadata: filepath.Join(dir, "metadata", "il-state-c.yaml"),
+			Output:   filepath.Join(dir, "results") + string(filepath.Separator),
+		},
+		{
+			// Named after its output before it is resolved
+			Name:    "results/naperville.yaml",
+			Overall: filepath.Join(dir, "pages", "naperville.csv"),
+			IsCSV:   true,
+			Output:  filepath.Join(dir, "results", "naperville.yaml"),
+		},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("got %d entries, want %d", len(entries), len(want))
+	}
+	for i := range want {
+		if entries[i] != want[i] {
+			t.Errorf("entry %d is %+v, want %+v", i, entries[i], want[i])
+		}
+	}
+}
+
+func TestLoadCSV(t *testing.T) {
+	dir := t.TempDir()
+	manifest := filepath.Join(dir, "season.csv")
+	contents := "name,overall,csv,output\nNaperville,naperville.csv,true,out/naperville.yaml\n"
+	if err := os.WriteFile(manifest, []byte(contents), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := batch.Load(manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name != "Naperville" || !entries[0].IsCSV || entries[0].Output != filepath.Join(dir, "out", "naperville.yaml") {
+		t.Errorf("got %+v", entries)
+	}
+}
+
+func TestLoadRejectsInvalidManifests(t *testing.T) {
+	dir := t.TempDir()
+	// An existing directory is an output directory even without a trailing
+	// separator
+	if err := os.Mkdir(filepath.Join(dir, "results"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name, manifest, contents string
+		wantErr                  string
+	}{
+		{"missing output", "season.yaml", "- overall: a.html\n", "tournament 1 needs both an overall table and an output"},
+		{"unknown column", "season.csv", "overall,output,division\na.html,a.yaml,c\n", `unknown column "division"`},
+		{"invalid csv flag", "season.csv", "overall,csv,output\na.csv,yes,a.yaml\n", "row 2: csv must be true or false"},
+		{"duplicate output", "season.yaml", "- {overall: a.html, output: out/a.yaml}\n- {overall: b.html, output: ./out/../out/a.yaml}\n", "tournaments 1 and 2 are both written to"},
+		{"shared output directory", "season.yaml", "- {overall: a.html, output: results}\n- {overall: b.html, output: results/}\n", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manifest := filepath.Join(dir, tt.manifest)
+			if err := os.WriteFile(manifest, []byte(tt.contents), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := batch.Load(manifest)
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("got %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("got %v, want an error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
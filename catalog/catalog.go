@@ -0,0 +1,116 @@
+package catalog
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed events.yaml
+var defaultCatalog []byte
+
+// Catalog lists the official events of each rules year and division, along
+// with the other names events go by
+type Catalog struct {
+	// Other names of each event, by canonical event name
+	Aliases map[string][]string `yaml:"aliases"`
+	// Events of each division, by rules year and division letter
+	Years map[int]map[string]Division `yaml:"years"`
+
+	// Canonical event names by normalized name or alias
+	canonicalNames map[string]string
+}
+
+type Division struct {
+	Events      []string `yaml:"events"`
+	TrialEvents []string `yaml:"trial events,omitempty"`
+}
+
+// Returns the catalog shipped with avocado2sciolyff
+func Default() *Catalog {
+	c, err := parse(defaultCatalog)
+	if err != nil {
+		panic(fmt.Sprintf("embedded event catalog is invalid: %v", err))
+	}
+	return c
+}
+
+// Reads an event catalog from a YAML file
+func Load(path string) (*Catalog, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	c, err := parse(contents)
+	if err != nil {
+		return nil, fmt.Errorf("could not read event catalog %s: %w", path, err)
+	}
+	return c, nil
+}
+
+func parse(contents []byte) (*Catalog, error) {
+	c := Catalog{}
+	if err := yaml.Unmarshal(contents, &c); err != nil {
+		return nil, err
+	}
+
+	c.canonicalNames = map[string]string{}
+	for year, divisions := range c.Years {
+		upperCaseDivisions := map[string]Division{}
+		for divisionName, division := range divisions {
+			upperCaseDivisions[strings.ToUpper(divisionName)] = division
+			for _, event := range slices.Concat(division.Events, division.TrialEvents) {
+				c.canonicalNames[normalize(event)] = event
+			}
+		}
+		c.Years[year] = upperCaseDivisions
+	}
+	for event, aliases := range c.Aliases {
+		c.canonicalNames[normalize(event)] = event
+		for _, alias := range aliases {
+			c.canonicalNames[normalize(alias)] = event
+		}
+	}
+	return &c, nil
+}
+
+// Reduces an event name to lower case letters and digits separated by single
+// spaces, with "&" spelled out and apostrophes dropped
+func normalize(name string) string {
+	name = strings.NewReplacer("&", " and ", "'", "", "’", "").Replace(strings.ToLower(name))
+	return strings.Join(strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
+
+// Returns the canonical name of an event, and whether the event is in the
+// catalog at all
+func (c *Catalog) CanonicalName(name string) (string, bool) {
+	canonical, ok := c.canonicalNames[normalize(name)]
+	if !ok {
+		return name, false
+	}
+	return canonical, true
+}
+
+// Returns the events of a division in a rules year, if the catalog has them
+func (c *Catalog) Division(year int, division string) (Division, bool) {
+	d, ok := c.Years[year][strings.ToUpper(division)]
+	return d, ok
+}
+
+// Whether the division lists the event (by canonical name) as an official event
+func (d Division) IsOfficial(event string) bool {
+	return slices.Contains(d.Events, event)
+}
+
+// Whether the division lists the event (by canonical name) as either an
+// official or a trial event
+func (d Division) IsKnown(event string) bool {
+	return d.IsOfficial(event) || slices.Contains(d.TrialEvents, event)
+}
@@ -0,0 +1,79 @@
+package catalog_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Nydauron/avocado2sciolyff/catalog"
+)
+
+func TestCanonicalName(t *testing.T) {
+	c := catalog.Default()
+	tests := []struct {
+		name   string
+		want   string
+		wantOK bool
+	}{
+		{"Codebusters", "Codebusters", true},
+		{"CODE BUSTERS", "Codebusters", true},
+		{"WIDI", "Write It Do It", true},
+		{"Anatomy & Physiology", "Anatomy and Physiology", true},
+		{"Cant Judge a Powder", "Can't Judge a Powder", true},
+		{"Exp. Design", "Experimental Design", true},
+		{"Robot Tour", "Robot Tour", true},
+		{"Underwater Basket Weaving", "Underwater Basket Weaving", false},
+	}
+	for _, tt := range tests {
+		if got, ok := c.CanonicalName(tt.name); got != tt.want || ok != tt.wantOK {
+			t.Errorf("CanonicalName(%q) = %q, %t, want %q, %t", tt.name, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestDivision(t *testing.T) {
+	c := catalog.Default()
+	division, ok := c.Division(2024, "c")
+	if !ok {
+		t.Fatal("the default catalog has no 2024 division C")
+	}
+	if !division.IsOfficial("Codebusters") || division.IsOfficial("Underwater Basket Weaving") {
+		t.Error("official events of 2024 division C are wrong")
+	}
+	if _, ok := c.Division(1999, "C"); ok {
+		t.Error("the default catalog has a 1999 division C")
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.yaml")
+	contents := `aliases:
+  Wind Power: [Wind]
+years:
+  2026:
+    c:
+      events: [Codebusters]
+      trial events: [Wind Power]
+`
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	c, err := catalog.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name, ok := c.CanonicalName("wind"); name != "Wind Power" || !ok {
+		t.Errorf("got %q, %t for an alias of a trial event", name, ok)
+	}
+	division, ok := c.Division(2026, "C")
+	if !ok || division.IsOfficial("Wind Power") || !division.IsKnown("Wind Power") {
+		t.Errorf("got division %+v, %t, want Wind Power as a trial event", division, ok)
+	}
+
+	if err := os.WriteFile(path, []byte("years: [2026]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := catalog.Load(path); err == nil {
+		t.Error("loading an invalid catalog did not fail")
+	}
+}
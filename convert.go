@@ -0,0 +1,478 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/Nydauron/avocado2sciolyff/catalog"
+	"github.com/Nydauron/avocado2sciolyff/corrections"
+	"github.com/Nydauron/avocado2sciolyff/metadata"
+	"github.com/Nydauron/avocado2sciolyff/output"
+	"github.com/Nydauron/avocado2sciolyff/parsers"
+	"github.com/Nydauron/avocado2sciolyff/prompts"
+	"github.com/Nydauron/avocado2sciolyff/schools"
+	"github.com/Nydauron/avocado2sciolyff/sciolyff"
+	sciolyff_models "github.com/Nydauron/avocado2sciolyff/sciolyff/models"
+	"github.com/Nydauron/avocado2sciolyff/validator"
+	"github.com/Nydauron/avocado2sciolyff/writers"
+	"github.com/urfave/cli/v2"
+)
+
+// Terminal the prompts, and the metadata editor, read from and write to.
+// Stdout is kept for the results.
+var promptInput, promptOutput = os.Stdin, os.Stderr
+
+// The flags of the convert command. They are also the flags of the app
+// itself, so that converting without naming the command keeps working.
+func convertFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.BoolFlag{
+			Name:  csvFlag,
+			Usage: "File passed in is a CSV rather than an HTML file",
+		},
+		&cli.StringFlag{
+			Name:    inputOverallFlag,
+			Aliases: []string{"iO"},
+			Usage:   "The URL or path to the HTML file containing the table of overall results to convert",
+		},
+		&cli.StringFlag{
+			Name:    inputGroupFlag,
+			Aliases: []string{"iG"},
+			Usage:   "The URL or path to the HTML file containing the table of results by grouping/track to convert",
+		},
+		&cli.StringFlag{
+			Name:    outputFlag,
+			Aliases: []string{"o"},
+			Usage:   "The location to write the result. Can be a file path, a directory (in which the file is named after the tournament) or \"-\" (for stdout).",
+		},
+		&cli.StringFlag{
+			Name:  placingOrderFlag,
+			Usage: "Order of the placings in the output: \"event\" (by event, then team number) or \"team\" (by team number, then event)",
+			Value: sciolyff.PlacingOrderEvent,
+		},
+		&cli.BoolFlag{
+			Name:  noValidateFlag,
+			Usage: "Write the results even if they break the sciolyff rules",
+		},
+		&cli.BoolFlag{
+			Name:  noClobberFlag,
+			Usage: "Refuse to overwrite an existing output file",
+		},
+		&cli.BoolFlag{
+			Name:  forceFlag,
+			Usage: "Overwrite the output file even with --" + noClobberFlag + " or when it holds a different tournament",
+		},
+		&cli.StringFlag{
+			Name:  formatFlag,
+			Usage: "Output format, one of \"yaml\", \"json\", \"csv\" with a row per placing, or a \"md\" or \"html\" results report. Defaults to the format matching the output file extension, or YAML",
+		},
+		&cli.IntFlag{
+			Name:  medalsFlag,
+			Usage: "Number of medals awarded per event. Prompted for (with a default based on the tournament level) if not set",
+		},
+		&cli.IntFlag{
+			Name:  trophiesFlag,
+			Usage: "Number of trophies awarded. Prompted for (with a default based on the tournament level) if not set",
+		},
+		&cli.IntFlag{
+			Name:  bidsFlag,
+			Usage: "Number of bids to the next tournament (regionals and states only)",
+		},
+		&cli.IntFlag{
+			Name:  bidsPerSchoolFlag,
+			Usage: "Maximum number of bids a single school can receive (regionals and states only)",
+		},
+		&cli.IntFlag{
+			Name:  worstDroppedFlag,
+			Usage: "Number of worst placings dropped from each team's total. Detected from the reported totals if not set",
+		},
+		&cli.StringFlag{
+			Name:  perEventNFlag,
+			Usage: "How N (the points for participating) is found per event: \"none\" (team count), \"place\" (highest place) or \"participation\" (teams participating). Detected from the scores if neither this nor --" + nOffsetFlag + " is set",
+		},
+		&cli.IntFlag{
+			Name:  nOffsetFlag,
+			Usage: "Offset added to N when scoring participation, no-shows and disqualifications",
+		},
+		&cli.StringFlag{
+			Name:    metadataFlag,
+			Aliases: []string{"m"},
+			Usage:   "Path to a YAML or TOML file with the tournament metadata and other answers to prompts. Only values missing from the file are prompted for",
+		},
+		&cli.StringFlag{
+			Name:  correctionsFlag,
+			Usage: "Path to a YAML or TOML file of corrections to teams and placings, applied to the results before they are written and noted in the output",
+		},
+		&cli.BoolFlag{
+			Name:  superscoreFlag,
+			Usage: "Write superscored results, where every school is one team with the best placing of any of its teams in each event",
+		},
+		&cli.BoolFlag{
+			Name:  noPromptFlag,
+			Usage: "Never prompt. Values with a default use it, and the conversion fails if a required value is missing",
+		},
+		&cli.StringFlag{
+			Name:  eventCatalogFlag,
+			Usage: "Path to a YAML event catalog listing the official events of each rules year and division, used instead of the built-in one",
+		},
+		&cli.StringFlag{
+			Name:  schoolAliasesFlag,
+			Usage: "Path to a YAML file mapping canonical school names to their aliases. School names are rewritten to their canonical names, and new aliases confirmed during conversion are saved to it",
+		},
+		&cli.StringFlag{
+			Name:  answersFlag,
+			Usage: "Path to a file with answers to the prompts, one per line in the order they are asked, or \"-\" to read them from stdin. An empty line takes the default",
+		},
+		&cli.BoolFlag{
+			Name:  editorFlag,
+			Usage: "Open a metadata template pre-filled with the inferred and default values in $EDITOR instead of prompting for the tournament details one by one",
+		},
+		&cli.StringFlag{
+			Name:  recordAnswersFlag,
+			Usage: "Path to write every answer given to the prompts to, keyed by prompt, so that they can be replayed with --" + replayAnswersFlag,
+		},
+		&cli.StringFlag{
+			Name:  replayAnswersFlag,
+			Usage: "Path to a file written by --" + recordAnswersFlag + ". Prompts with a recorded answer are answered from it, and any others are asked as usual",
+		},
+	}
+}
+
+// Converts the results tables given by the flags to sciolyff
+func convertHandle(cCtx *cli.Context) error {
+	// Checked here rather than marked as required so that the other commands
+	// can run without them when converting is the default command
+	inputOverallLocation := cCtx.String(inputOverallFlag)
+	if inputOverallLocation == "" {
+		return fmt.Errorf("--%s not set", inputOverallFlag)
+	}
+	outputLocation := cCtx.String(outputFlag)
+	if outputLocation == "" {
+		return fmt.Errorf("--%s not set", outputFlag)
+	}
+	format := output.FormatForPath(outputLocation)
+	if cCtx.IsSet(formatFlag) {
+		var err error
+		if format, err = output.ParseFormat(cCtx.String(formatFlag)); err != nil {
+			return fmt.Errorf("--%s: %w", formatFlag, err)
+		}
+	}
+	placingOrder, err := sciolyff.ParsePlacingOrder(cCtx.String(placingOrderFlag))
+	if err != nil {
+		return fmt.Errorf("--%s: %w", placingOrderFlag, err)
+	}
+	opts := sciolyff.Options{PlacingOrder: placingOrder}
+	// Checked in the order the flags are listed, so that the same flags
+	// always report the same error
+	for _, flag := range []struct {
+		name   string
+		option **int
+	}{
+		{medalsFlag, &opts.Medals},
+		{trophiesFlag, &opts.Trophies},
+		{bidsFlag, &opts.Bids},
+		{bidsPerSchoolFlag, &opts.BidsPerSchool},
+		{worstDroppedFlag, &opts.WorstPlacingsDropped},
+	} {
+		if cCtx.IsSet(flag.name) {
+			value := cCtx.Int(flag.name)
+			if value < 0 {
+				return fmt.Errorf("--%s must not be negative", flag.name)
+			}
+			*flag.option = &value
+		}
+	}
+	if cCtx.IsSet(perEventNFlag) || cCtx.IsSet(nOffsetFlag) {
+		perEventN, err := sciolyff.ParsePerEventN(cCtx.String(perEventNFlag))
+		if err != nil {
+			return fmt.Errorf("--%s: %w", perEventNFlag, err)
+		}
+		scoring := sciolyff.ScoringModel{PerEventN: perEventN, NOffset: cCtx.Int(nOffsetFlag)}
+		if scoring.NOffset < 0 {
+			return fmt.Errorf("--%s must not be negative", nOffsetFlag)
+		}
+		opts.Scoring = &scoring
+	}
+	if metadataLocation := cCtx.String(metadataFlag); metadataLocation != "" {
+		metadataFile, err := metadata.Load(metadataLocation)
+		if err != nil {
+			return err
+		}
+		if err := metadataFile.Apply(&opts); err != nil {
+			return fmt.Errorf("invalid metadata file %s: %w", metadataLocation, err)
+		}
+	}
+	opts.Catalog = catalog.Default()
+	if catalogLocation := cCtx.String(eventCatalogFlag); catalogLocation != "" {
+		eventCatalog, err := catalog.Load(catalogLocation)
+		if err != nil {
+			return err
+		}
+		opts.Catalog = eventCatalog
+	}
+	if aliasesLocation := cCtx.String(schoolAliasesFlag); aliasesLocation != "" {
+		schoolAliases, err := schools.Load(aliasesLocation)
+		if err != nil {
+			return err
+		}
+		opts.SchoolAliases = schoolAliases
+	}
+	var correctionsFile *corrections.File
+	if correctionsLocation := cCtx.String(correctionsFlag); correctionsLocation != "" {
+		if correctionsFile, err = corrections.Load(correctionsLocation); err != nil {
+			return err
+		}
+	}
+
+	var prompter prompts.Prompter = prompts.NewTerminalPrompter(promptInput, promptOutput)
+	if cCtx.Bool(noPromptFlag) {
+		if cCtx.IsSet(answersFlag) {
+			return fmt.Errorf("--%s and --%s cannot be used together", noPromptFlag, answersFlag)
+		}
+		prompter = prompts.DefaultsPrompter{}
+	} else if answersLocation := cCtx.String(answersFlag); answersLocation == stdoutCLIName {
+		prompter = prompts.NewScriptedPrompter(os.Stdin)
+	} else if answersLocation != "" {
+		answersFile, err := os.Open(answersLocation)
+		if err != nil {
+			return err
+		}
+		defer answersFile.Close()
+		prompter = prompts.NewScriptedPrompter(answersFile)
+	}
+	if replayLocation := cCtx.String(replayAnswersFlag); replayLocation != "" {
+		answers, err := prompts.LoadAnswers(replayLocation)
+		if err != nil {
+			return err
+		}
+		prompter = prompts.NewReplayingPrompter(answers, prompter)
+	}
+	recordLocation := cCtx.String(recordAnswersFlag)
+	var recorder *prompts.RecordingPrompter
+	if recordLocation != "" {
+		recorder = prompts.NewRecordingPrompter(prompter)
+		prompter = recorder
+	}
+
+	openOutput, err := newOutputOpener(outputLocation, format, cCtx.Bool(noClobberFlag), cCtx.Bool(forceFlag), os.Stderr, nil)
+	if err != nil {
+		return err
+	}
+	err = convert(conversion{
+		inputLocation:        inputOverallLocation,
+		inputByGroupLocation: cCtx.String(inputGroupFlag),
+		isCSVFile:            cCtx.Bool(csvFlag),
+		openOutput:           openOutput,
+		format:               format,
+		useEditor:            cCtx.Bool(editorFlag),
+		shouldValidate:       !cCtx.Bool(noValidateFlag),
+		isSuperscored:        cCtx.Bool(superscoreFlag),
+		corrections:          correctionsFile,
+		prompter:             prompter,
+		opts:                 opts,
+	})
+	// Answers are saved even if the conversion failed, so that a re-run
+	// only asks what was left unanswered
+	if recorder != nil {
+		if saveErr := recorder.Save(recordLocation); saveErr != nil {
+			return errors.Join(err, fmt.Errorf("could not save answers: %w", saveErr))
+		}
+	}
+	return err
+}
+
+// A single tournament to convert, and how
+type conversion struct {
+	inputLocation string
+	// Table of results by track. Skipped if empty.
+	inputByGroupLocation string
+	isCSVFile            bool
+	openOutput           outputOpener
+	format               output.Format
+	// Whether the tournament details are edited in a metadata template
+	// rather than prompted for one by one
+	useEditor      bool
+	shouldValidate bool
+	isSuperscored  bool
+	// Applied before superscoring and validating. Skipped if nil.
+	corrections *corrections.File
+	prompter    prompts.Prompter
+	opts        sciolyff.Options
+}
+
+// Reads, converts and writes the results of a tournament. Errors are returned
+// rather than logged, so that the caller reports them once.
+func convert(c conversion) error {
+	inputLocation, inputByGroupLocation, opts := c.inputLocation, c.inputByGroupLocation, c.opts
+	logWriter := opts.LogWriter()
+	var overallResTable *parsers.Table = nil
+	var groupResTable *parsers.Table = nil
+	err_ch := make(chan error, 2)
+	wg := sync.WaitGroup{}
+
+	dataParser := func(err_channel chan<- error, inputPath string, table **parsers.Table) {
+		defer wg.Done()
+		t, err := readTable(inputPath, c.isCSVFile, logWriter)
+		*table = t
+		if err != nil {
+			err_channel <- err
+		}
+	}
+	wg.Add(1)
+	go dataParser(err_ch, inputLocation, &overallResTable)
+
+	if inputByGroupLocation != "" {
+		wg.Add(1)
+		go dataParser(err_ch, inputByGroupLocation, &groupResTable)
+	}
+	// Both tables are read before returning, even if one fails, so that no
+	// reader is left behind
+	wg.Wait()
+	close(err_ch)
+	if err := <-err_ch; err != nil {
+		return err
+	}
+
+	opts.Inferred = inferMetadata(inputLocation, overallResTable)
+	if groupResTable != nil {
+		opts.Inferred.Merge(inferMetadata(inputByGroupLocation, groupResTable))
+	}
+
+	if c.useEditor {
+		editedMetadata, err := metadata.Edit(metadata.Template(*overallResTable, groupResTable, opts), promptInput, promptOutput)
+		if err != nil {
+			return err
+		}
+		// Award defaults depend on the level, which is only final now
+		editedMetadata.FillAwardDefaults(*overallResTable)
+		// The template already holds every preset value, so the edited file
+		// replaces them rather than only filling in what is missing
+		editedOpts := sciolyff.Options{Catalog: opts.Catalog, SchoolAliases: opts.SchoolAliases, Inferred: opts.Inferred, PlacingOrder: opts.PlacingOrder, Log: opts.Log}
+		if err := editedMetadata.Apply(&editedOpts); err != nil {
+			return err
+		}
+		opts = editedOpts
+	}
+
+	sciolyffDump, err := sciolyff.GenerateSciolyFF(*overallResTable, groupResTable, c.prompter, opts)
+	if err != nil {
+		return err
+	}
+
+	appliedCorrections := []string{}
+	if c.corrections != nil {
+		if appliedCorrections, err = c.corrections.Apply(&sciolyffDump, opts.Catalog); err != nil {
+			return err
+		}
+		for _, correction := range appliedCorrections {
+			fmt.Fprintf(logWriter, "Corrected %s\n", correction)
+		}
+	}
+
+	if c.isSuperscored {
+		sciolyffDump = sciolyff.Superscore(sciolyffDump, opts.PlacingOrder)
+	}
+
+	if c.shouldValidate {
+		if problems := validator.Validate(sciolyffDump); len(problems) > 0 {
+			for _, problem := range problems {
+				fmt.Fprintf(logWriter, "Invalid result: %s\n", problem)
+			}
+			return fmt.Errorf("generated results have %d problems. Use --%s to write them anyway", len(problems), noValidateFlag)
+		}
+	}
+
+	outputWriter, err := c.openOutput(sciolyffDump)
+	if err != nil {
+		return err
+	}
+	if err := output.Encode(outputWriter, sciolyffDump, c.format, semanticVersion, appliedCorrections); err != nil {
+		outputWriter.Abort()
+		return cli.Exit(fmt.Sprintf("encoding to %s failed: %v", strings.ToUpper(string(c.format)), err), 3)
+	}
+	if err := outputWriter.Close(); err != nil {
+		return err
+	}
+	// Aliases recorded while prompting are only kept once the results they
+	// were recorded for have been written
+	if opts.SchoolAliases != nil {
+		if err := opts.SchoolAliases.Save(); err != nil {
+			return fmt.Errorf("could not save school aliases: %w", err)
+		}
+	}
+	return nil
+}
+
+// Opens where the results are written to, which can depend on the results
+// themselves
+type outputOpener func(sciolyffDump sciolyff_models.SciolyFF) (writers.AbortWriteCloser, error)
+
+// Writes to stdout, to a file, or in directory mode to a file in the
+// directory named after the tournament. The output is a directory if it
+// already is one or ends with a path separator. Files are replaced atomically,
+// and existing files are kept with noClobber. Unless forced, a file in the
+// directory holding a different tournament is never replaced. The file chosen
+// in directory mode is noted in logWriter. With locks, the file is locked from
+// when it is checked until the writer is closed or aborted.
+func newOutputOpener(outputLocation string, format output.Format, noClobber bool, isForced bool, logWriter io.Writer, locks *pathLocks) (outputOpener, error) {
+	if outputLocation == stdoutCLIName {
+		return func(sciolyff_models.SciolyFF) (writers.AbortWriteCloser, error) {
+			return writers.NopWriteCloser(os.Stdout), nil
+		}, nil
+	}
+	noClobber = noClobber && !isForced
+	isDirectory := output.IsDirectory(outputLocation)
+	// Fail before any prompting when the output file is already known
+	if _, err := os.Stat(outputLocation); err == nil && !isDirectory && noClobber {
+		return nil, fmt.Errorf("refusing to overwrite %s. Use --%s to overwrite it", outputLocation, forceFlag)
+	}
+	return func(sciolyffDump sciolyff_models.SciolyFF) (writers.AbortWriteCloser, error) {
+		path := outputLocation
+		if isDirectory {
+			if err := os.MkdirAll(outputLocation, 0755); err != nil {
+				return nil, err
+			}
+			path = filepath.Join(outputLocation, output.FileName(sciolyffDump.Tournament, format))
+		}
+		unlock := func() {}
+		if locks != nil {
+			unlock = locks.lock(path)
+		}
+		if isDirectory {
+			if !isForced {
+				if err := output.CheckCollision(path, sciolyffDump); err != nil {
+					unlock()
+					return nil, fmt.Errorf("%w. Use --%s to replace it anyway", err, forceFlag)
+				}
+			}
+			fmt.Fprintf(logWriter, "Writing results to %s\n", path)
+		}
+		if _, err := os.Stat(path); err == nil && noClobber {
+			unlock()
+			return nil, fmt.Errorf("refusing to overwrite %s. Use --%s to overwrite it", path, forceFlag)
+		}
+		return unlockingWriteCloser{writers.NewAtomicWriteCloser(path, noClobber), unlock}, nil
+	}, nil
+}
+
+// Releases the lock on the file written to once the writer is closed or
+// aborted
+type unlockingWriteCloser struct {
+	writers.AbortWriteCloser
+	unlock func()
+}
+
+func (w unlockingWriteCloser) Close() error {
+	defer w.unlock()
+	return w.AbortWriteCloser.Close()
+}
+
+func (w unlockingWriteCloser) Abort() error {
+	defer w.unlock()
+	return w.AbortWriteCloser.Abort()
+}
@@ -0,0 +1,173 @@
+package corrections
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/Nydauron/avocado2sciolyff/catalog"
+	"github.com/Nydauron/avocado2sciolyff/sciolyff"
+	sciolyff_models "github.com/Nydauron/avocado2sciolyff/sciolyff/models"
+	"gopkg.in/yaml.v3"
+)
+
+// File holds corrections to results that are known to be wrong on Avogadro,
+// e.g. a place confirmed by the tournament director. Only the fields given in
+// a correction are changed.
+type File struct {
+	Teams    []Team    `yaml:"teams,omitempty" toml:"teams,omitempty"`
+	Placings []Placing `yaml:"placings,omitempty" toml:"placings,omitempty"`
+}
+
+// Team corrects the team with the given number
+type Team struct {
+	Number uint    `yaml:"number" toml:"number"`
+	School *string `yaml:"school,omitempty" toml:"school,omitempty"`
+	Track  *string `yaml:"track,omitempty" toml:"track,omitempty"`
+	// Why the correction was made, noted alongside it in the output
+	Reason string `yaml:"reason,omitempty" toml:"reason,omitempty"`
+}
+
+// Placing corrects the placing of the given team in the given event. The event
+// can be named as on Avogadro or as in the output. Ties are not corrected
+// directly, as they follow from the places.
+type Placing struct {
+	Team         uint   `yaml:"team" toml:"team"`
+	Event        string `yaml:"event" toml:"event"`
+	Place        *uint  `yaml:"place,omitempty" toml:"place,omitempty"`
+	TrackPlace   *uint  `yaml:"track place,omitempty" toml:"track place,omitempty"`
+	Participated *bool  `yaml:"participated,omitempty" toml:"participated,omitempty"`
+	Disqualified *bool  `yaml:"disqualified,omitempty" toml:"disqualified,omitempty"`
+	Exempt       *bool  `yaml:"exempt,omitempty" toml:"exempt,omitempty"`
+	Unknown      *bool  `yaml:"unknown,omitempty" toml:"unknown,omitempty"`
+	// Why the correction was made, noted alongside it in the output
+	Reason string `yaml:"reason,omitempty" toml:"reason,omitempty"`
+}
+
+// Reads a corrections file. Files ending in ".toml" are read as TOML,
+// everything else as YAML.
+func Load(path string) (*File, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	f := File{}
+	if strings.EqualFold(filepath.Ext(path), ".toml") {
+		err = toml.Unmarshal(contents, &f)
+	} else {
+		err = yaml.Unmarshal(contents, &f)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read corrections file %s: %w", path, err)
+	}
+	return &f, nil
+}
+
+// Apply makes the corrections to the results and describes each one, e.g.
+// `team 5 in Codebusters: place from 4 to 3 (confirmed by the TD)`. Every
+// correction must target an existing team or placing. Event names are matched
+// through the event catalog, if given, so that names the catalog renamed still
+// match. Ties are marked again once every correction is made, and so are the
+// track places of the events whose places or teams changed, unless a track
+// place in the event was itself corrected.
+func (f *File) Apply(s *sciolyff_models.SciolyFF, eventCatalog *catalog.Catalog) ([]string, error) {
+	hasTrackPlaces := slices.ContainsFunc(s.Placings, func(p sciolyff_models.Placing) bool { return p.TrackPlace != 0 })
+	// Events whose track places are out of date, and events whose track
+	// places were corrected by hand
+	rerankedEvents, correctedEvents := map[string]bool{}, map[string]bool{}
+	applied := []string{}
+	for _, c := range f.Teams {
+		i := slices.IndexFunc(s.Teams, func(t sciolyff_models.School) bool { return t.TeamNumber == c.Number })
+		if i == -1 {
+			return nil, fmt.Errorf("correction to team %d: no such team", c.Number)
+		}
+		if c.Track != nil && *c.Track != "" && !slices.ContainsFunc(s.Tracks, func(t sciolyff_models.Track) bool { return t.Name == *c.Track }) {
+			return nil, fmt.Errorf("correction to team %d: track %q is not one of the tracks", c.Number, *c.Track)
+		}
+		team := &s.Teams[i]
+		if c.Track != nil && *c.Track != team.Track {
+			for _, p := range s.Placings {
+				if p.TeamNumber == c.Number {
+					rerankedEvents[p.Event] = true
+				}
+			}
+		}
+		changes := []string{}
+		correct(&changes, "school", &team.Name, c.School)
+		correct(&changes, "track", &team.Track, c.Track)
+		applied = append(applied, describe(fmt.Sprintf("team %d", c.Number), changes, c.Reason))
+	}
+	for _, c := range f.Placings {
+		event := c.Event
+		if eventCatalog != nil && !slices.ContainsFunc(s.Events, func(e sciolyff_models.Event) bool { return e.Name == event }) {
+			event, _ = eventCatalog.CanonicalName(c.Event)
+		}
+		i := slices.IndexFunc(s.Placings, func(p sciolyff_models.Placing) bool { return p.TeamNumber == c.Team && p.Event == event })
+		if i == -1 {
+			return nil, fmt.Errorf("correction to team %d in %s: no such placing", c.Team, c.Event)
+		}
+		p := &s.Placings[i]
+		if c.TrackPlace != nil {
+			correctedEvents[event] = true
+		} else if c.Place != nil || c.Participated != nil || c.Disqualified != nil || c.Exempt != nil || c.Unknown != nil {
+			rerankedEvents[event] = true
+		}
+		changes := []string{}
+		correct(&changes, "place", &p.Place, c.Place)
+		correct(&changes, "track place", &p.TrackPlace, c.TrackPlace)
+		correct(&changes, "participated", &p.Participated, c.Participated)
+		correct(&changes, "disqualified", &p.EventDQ, c.Disqualified)
+		correct(&changes, "exempt", &p.Exempt, c.Exempt)
+		correct(&changes, "unknown", &p.Unknown, c.Unknown)
+		applied = append(applied, describe(fmt.Sprintf("team %d in %s", c.Team, event), changes, c.Reason))
+	}
+	sciolyff.MarkTies(s)
+	if hasTrackPlaces {
+		events := []string{}
+		for event := range rerankedEvents {
+			if !correctedEvents[event] {
+				events = append(events, event)
+			}
+		}
+		sciolyff.RankTrackPlaces(s, events)
+	}
+	return applied, nil
+}
+
+// Sets value to the corrected value if one is given, noting the change
+func correct[T comparable](changes *[]string, name string, value *T, corrected *T) {
+	if corrected == nil || *value == *corrected {
+		return
+	}
+	*changes = append(*changes, fmt.Sprintf("%s from %s to %s", name, describeValue(*value), describeValue(*corrected)))
+	*value = *corrected
+}
+
+func describeValue(value any) string {
+	switch value {
+	case uint(0), "":
+		return "none"
+	}
+	if s, ok := value.(string); ok {
+		return fmt.Sprintf("%q", s)
+	}
+	return fmt.Sprint(value)
+}
+
+func describe(target string, changes []string, reason string) string {
+	description := target + ": "
+	if len(changes) == 0 {
+		description += "already as corrected"
+	} else {
+		description += strings.Join(changes, ", ")
+	}
+	// Kept to one line so that it fits in a comment
+	if reason := strings.Join(strings.Fields(reason), " "); reason != "" {
+		description += " (" + reason + ")"
+	}
+	return description
+}
@@ -0,0 +1,115 @@
+package corrections_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Nydauron/avocado2sciolyff/corrections"
+	sciolyff_models "github.com/Nydauron/avocado2sciolyff/sciolyff/models"
+)
+
+func load(t *testing.T, name, contents string) *corrections.File {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	f, err := corrections.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+// Three varsity teams and a JV team in Codebusters
+func results() sciolyff_models.SciolyFF {
+	return sciolyff_models.SciolyFF{
+		Tracks: []sciolyff_models.Track{{Name: "JV"}, {Name: "V"}},
+		Events: []sciolyff_models.Event{{Name: "Codebusters"}},
+		Teams: []sciolyff_models.School{
+			{TeamNumber: 1, Name: "Troy HS", Track: "V"},
+			{TeamNumber: 2, Name: "Lincoln HS", Track: "V"},
+			{TeamNumber: 3, Name: "Oak HS", Track: "V"},
+			{TeamNumber: 4, Name: "Troy HS", Track: "JV"},
+		},
+		Placings: []sciolyff_models.Placing{
+			{Event: "Codebusters", TeamNumber: 1, Participated: true, Place: 1, TrackPlace: 1},
+			{Event: "Codebusters", TeamNumber: 2, Participated: true, Place: 2, TrackPlace: 2},
+			{Event: "Codebusters", TeamNumber: 3, Participated: true, Place: 3, TrackPlace: 3},
+			{Event: "Codebusters", TeamNumber: 4, Participated: true, Place: 4, TrackPlace: 1},
+		},
+	}
+}
+
+func TestApply(t *testing.T) {
+	f := load(t, "corrections.toml", `
+[[teams]]
+number = 1
+school = "Troy High School"
+
+[[placings]]
+team = 3
+event = "Codebusters"
+place = 2
+reason = """Confirmed by
+  the tournament director"""
+`)
+	s := results()
+	applied, err := f.Apply(&s, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `team 1: school from "Troy HS" to "Troy High School"
+team 3 in Codebusters: place from 3 to 2 (Confirmed by the tournament director)`
+	if got := strings.Join(applied, "\n"); got != want {
+		t.Errorf("applied:\n%s\nwant:\n%s", got, want)
+	}
+	// Lincoln and Oak now share second place, in the varsity track as well
+	for i, want := range []struct {
+		tie        bool
+		trackPlace uint
+	}{{false, 1}, {true, 2}, {true, 2}, {false, 1}} {
+		if p := s.Placings[i]; p.Tie != want.tie || p.TrackPlace != want.trackPlace {
+			t.Errorf("team %d has tie %t and track place %d, want %t and %d", p.TeamNumber, p.Tie, p.TrackPlace, want.tie, want.trackPlace)
+		}
+	}
+}
+
+func TestApplyMovesTeamsBetweenTracks(t *testing.T) {
+	f := load(t, "corrections.yaml", "teams:\n  - number: 1\n    track: JV\n")
+	s := results()
+	if _, err := f.Apply(&s, nil); err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []uint{1, 1, 2, 2} {
+		if got := s.Placings[i].TrackPlace; got != want {
+			t.Errorf("team %d has track place %d, want %d", s.Placings[i].TeamNumber, got, want)
+		}
+	}
+
+	// A track place corrected by hand is kept as given
+	f = load(t, "corrections.yaml", "teams:\n  - number: 1\n    track: JV\nplacings:\n  - team: 4\n    event: Codebusters\n    track place: 1\n")
+	s = results()
+	if _, err := f.Apply(&s, nil); err != nil {
+		t.Fatal(err)
+	}
+	if s.Placings[0].TrackPlace != 1 || s.Placings[3].TrackPlace != 1 {
+		t.Errorf("got track places %d and %d for the JV teams", s.Placings[0].TrackPlace, s.Placings[3].TrackPlace)
+	}
+}
+
+func TestApplyRejectsMissingTargets(t *testing.T) {
+	tests := map[string]string{
+		"teams:\n  - number: 9\n    school: Oak HS\n":                   "correction to team 9: no such team",
+		"teams:\n  - number: 1\n    track: Varsity\n":                   `correction to team 1: track "Varsity" is not one of the tracks`,
+		"placings:\n  - team: 1\n    event: Wind Power\n    place: 1\n": "correction to team 1 in Wind Power: no such placing",
+	}
+	for contents, want := range tests {
+		s := results()
+		if _, err := load(t, "corrections.yaml", contents).Apply(&s, nil); err == nil || err.Error() != want {
+			t.Errorf("got %v, want %q", err, want)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/Nydauron/avocado2sciolyff/diff"
+	"github.com/Nydauron/avocado2sciolyff/output"
+	"github.com/urfave/cli/v2"
+)
+
+// Lists every difference between the old and new results files given as
+// arguments
+func diffHandle(cCtx *cli.Context) error {
+	if cCtx.NArg() != 2 {
+		return fmt.Errorf("expected an old and a new file to compare, got %d files", cCtx.NArg())
+	}
+	oldDump, err := output.Load(cCtx.Args().Get(0))
+	if err != nil {
+		return err
+	}
+	newDump, err := output.Load(cCtx.Args().Get(1))
+	if err != nil {
+		return err
+	}
+	changes := diff.Compare(oldDump, newDump)
+	for _, change := range changes {
+		fmt.Println(change)
+	}
+	if len(changes) > 0 {
+		return cli.Exit(fmt.Sprintf("%d differences found", len(changes)), 1)
+	}
+	fmt.Println("No differences found")
+	return nil
+}
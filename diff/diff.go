@@ -0,0 +1,203 @@
+package diff
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+
+	sciolyff_models "github.com/Nydauron/avocado2sciolyff/sciolyff/models"
+)
+
+// Change is a single difference between two sets of results
+type Change struct {
+	// The changed entry, e.g. `Placings (event Codebusters, team 5)`
+	Entry   string
+	Message string
+}
+
+func (c Change) String() string {
+	return c.Entry + ": " + c.Message
+}
+
+type changes []Change
+
+func (cs *changes) add(entry string, format string, args ...any) {
+	*cs = append(*cs, Change{Entry: entry, Message: fmt.Sprintf(format, args...)})
+}
+
+// A named value of an entry, compared by its printed form
+type field struct {
+	name     string
+	old, new any
+}
+
+func (cs *changes) addFields(entry string, fields []field) {
+	for _, f := range fields {
+		if old, new := fmt.Sprint(f.old), fmt.Sprint(f.new); old != new {
+			cs.add(entry, "%s changed from %s to %s", f.name, quoteOrNone(old), quoteOrNone(new))
+		}
+	}
+}
+
+func quoteOrNone(value string) string {
+	if value == "" || value == "0" {
+		return "none"
+	}
+	if _, err := strconv.Atoi(value); err == nil || value == "true" || value == "false" {
+		return value
+	}
+	return strconv.Quote(value)
+}
+
+// Compare returns every difference between the old and new results. Entries
+// are matched by name or number rather than by their position, so the order
+// of either file does not matter.
+func Compare(old, new sciolyff_models.SciolyFF) []Change {
+	cs := changes{}
+	compareTournaments(&cs, old.Tournament, new.Tournament)
+	compareTracks(&cs, old.Tracks, new.Tracks)
+	compareEvents(&cs, old.Events, new.Events)
+	compareTeams(&cs, old.Teams, new.Teams)
+	comparePlacings(&cs, old.Placings, new.Placings)
+	return cs
+}
+
+func compareTournaments(cs *changes, old, new sciolyff_models.TournamentMetadata) {
+	cs.addFields("Tournament", []field{
+		{"name", old.Name, new.Name},
+		{"short name", old.ShortName, new.ShortName},
+		{"location", old.Location, new.Location},
+		{"level", old.Level, new.Level},
+		{"state", old.State, new.State},
+		{"division", old.Division, new.Division},
+		{"year", old.Year, new.Year},
+		{"date", old.Date, new.Date},
+		{"start date", old.StartDate, new.StartDate},
+		{"end date", old.EndDate, new.EndDate},
+		{"awards date", old.AwardsDate, new.AwardsDate},
+		{"test date", old.TestDate, new.TestDate},
+		{"medals", old.Medals, new.Medals},
+		{"trophies", old.Trophies, new.Trophies},
+		{"bids", old.Bids, new.Bids},
+		{"bids per school", old.BidsPerSchool, new.BidsPerSchool},
+		{"worst placings dropped", old.WorstPlacingsDropped, new.WorstPlacingsDropped},
+		{"n offset", old.NOffset, new.NOffset},
+		{"per-event n", old.PerEventN, new.PerEventN},
+	})
+}
+
+// Indexes entries by their key, returning the keys of both old and new entries
+// sorted with compare
+func index[T any, K comparable](old, new []T, key func(T) K, compare func(a, b K) int) (map[K]T, map[K]T, []K) {
+	oldByKey, newByKey := map[K]T{}, map[K]T{}
+	for _, entry := range old {
+		oldByKey[key(entry)] = entry
+	}
+	for _, entry := range new {
+		newByKey[key(entry)] = entry
+	}
+	keys := slices.Collect(maps.Keys(oldByKey))
+	for k := range newByKey {
+		if _, ok := oldByKey[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	slices.SortFunc(keys, compare)
+	return oldByKey, newByKey, keys
+}
+
+func compareTracks(cs *changes, old, new []sciolyff_models.Track) {
+	oldTracks, newTracks, names := index(old, new, func(t sciolyff_models.Track) string { return t.Name }, cmp.Compare)
+	for _, name := range names {
+		entry := fmt.Sprintf("Tracks (%s)", name)
+		o, isInOld := oldTracks[name]
+		n, isInNew := newTracks[name]
+		switch {
+		case !isInOld:
+			cs.add(entry, "added")
+		case !isInNew:
+			cs.add(entry, "removed")
+		default:
+			cs.addFields(entry, []field{
+				{"medals", o.Medals, n.Medals},
+				{"trophies", o.Trophies, n.Trophies},
+			})
+		}
+	}
+}
+
+func compareEvents(cs *changes, old, new []sciolyff_models.Event) {
+	oldEvents, newEvents, names := index(old, new, func(e sciolyff_models.Event) string { return e.Name }, cmp.Compare)
+	for _, name := range names {
+		entry := fmt.Sprintf("Events (%s)", name)
+		o, isInOld := oldEvents[name]
+		n, isInNew := newEvents[name]
+		switch {
+		case !isInOld:
+			cs.add(entry, "added")
+		case !isInNew:
+			cs.add(entry, "removed")
+		default:
+			cs.addFields(entry, []field{
+				{"trial", o.IsTrial, n.IsTrial},
+				{"trialed", o.TrialedNormalEvent, n.TrialedNormalEvent},
+				{"scoring", o.ScoringObjective, n.ScoringObjective},
+			})
+		}
+	}
+}
+
+func compareTeams(cs *changes, old, new []sciolyff_models.School) {
+	oldTeams, newTeams, numbers := index(old, new, func(t sciolyff_models.School) uint { return t.TeamNumber }, cmp.Compare)
+	for _, number := range numbers {
+		entry := fmt.Sprintf("Teams (team %d)", number)
+		o, isInOld := oldTeams[number]
+		n, isInNew := newTeams[number]
+		switch {
+		case !isInOld:
+			cs.add(entry, "added (%s)", n.Name)
+		case !isInNew:
+			cs.add(entry, "removed (%s)", o.Name)
+		default:
+			cs.addFields(entry, []field{
+				{"school", o.Name, n.Name},
+				{"track", o.Track, n.Track},
+			})
+		}
+	}
+}
+
+type eventTeam struct {
+	event string
+	team  uint
+}
+
+func comparePlacings(cs *changes, old, new []sciolyff_models.Placing) {
+	oldPlacings, newPlacings, keys := index(old, new,
+		func(p sciolyff_models.Placing) eventTeam { return eventTeam{p.Event, p.TeamNumber} },
+		func(a, b eventTeam) int { return cmp.Or(cmp.Compare(a.event, b.event), cmp.Compare(a.team, b.team)) },
+	)
+	for _, k := range keys {
+		entry := fmt.Sprintf("Placings (event %s, team %d)", k.event, k.team)
+		o, isInOld := oldPlacings[k]
+		n, isInNew := newPlacings[k]
+		switch {
+		case !isInOld:
+			cs.add(entry, "added")
+		case !isInNew:
+			cs.add(entry, "removed")
+		default:
+			cs.addFields(entry, []field{
+				{"place", o.Place, n.Place},
+				{"track place", o.TrackPlace, n.TrackPlace},
+				{"tie", o.Tie, n.Tie},
+				{"participated", o.Participated, n.Participated},
+				{"disqualified", o.EventDQ, n.EventDQ},
+				{"exempt", o.Exempt, n.Exempt},
+				{"unknown", o.Unknown, n.Unknown},
+			})
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package diff_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Nydauron/avocado2sciolyff/diff"
+	sciolyff_models "github.com/Nydauron/avocado2sciolyff/sciolyff/models"
+)
+
+func lines(changes []diff.Change) string {
+	printed := []string{}
+	for _, change := range changes {
+		printed = append(printed, change.String())
+	}
+	return strings.Join(printed, "\n")
+}
+
+func TestCompare(t *testing.T) {
+	old := sciolyff_models.SciolyFF{
+		Tournament: sciolyff_models.TournamentMetadata{Name: "Naperville Invitational", Level: "Invitational", Medals: 3},
+		Events:     []sciolyff_models.Event{{Name: "Codebusters"}, {Name: "Wind Power"}},
+		Teams:      []sciolyff_models.School{{TeamNumber: 1, Name: "Troy HS"}, {TeamNumber: 2, Name: "Lincoln HS"}},
+		Placings: []sciolyff_models.Placing{
+			{Event: "Codebusters", TeamNumber: 1, Participated: true, Place: 1},
+			{Event: "Codebusters", TeamNumber: 2, Participated: true, Place: 2},
+			{Event: "Wind Power", TeamNumber: 1, Participated: true, Place: 1},
+		},
+	}
+	new := sciolyff_models.SciolyFF{
+		Tournament: sciolyff_models.TournamentMetadata{Name: "Naperville Invitational", ShortName: "Naperville", Level: "Invitational", Medals: 5},
+		Events:     []sciolyff_models.Event{{Name: "Codebusters"}, {Name: "Wind Power", IsTrial: true}},
+		Teams:      []sciolyff_models.School{{TeamNumber: 1, Name: "Troy High School"}, {TeamNumber: 3, Name: "Oak HS"}},
+		Placings: []sciolyff_models.Placing{
+			{Event: "Codebusters", TeamNumber: 1, Participated: true, Place: 1, Tie: true},
+			{Event: "Codebusters", TeamNumber: 3, Participated: true, Place: 1, Tie: true},
+			{Event: "Wind Power", TeamNumber: 1},
+		},
+	}
+	want := `Tournament: short name changed from none to "Naperville"
+Tournament: medals changed from 3 to 5
+Events (Wind Power): trial changed from false to true
+Teams (team 1): school changed from "Troy HS" to "Troy High School"
+Teams (team 2): removed (Lincoln HS)
+Teams (team 3): added (Oak HS)
+Placings (event Codebusters, team 1): tie changed from false to true
+Placings (event Codebusters, team 2): removed
+Placings (event Codebusters, team 3): added
+Placings (event Wind Power, team 1): place changed from 1 to none
+Placings (event Wind Power, team 1): participated changed from true to false`
+	if got := lines(diff.Compare(old, new)); got != want {
+		t.Errorf("got changes:\n%s\nwant:\n%s", got, want)
+	}
+
+	// Results listed in another order are the same results
+	reordered := old
+	reordered.Placings = slices.Clone(old.Placings)
+	slices.Reverse(reordered.Placings)
+	reordered.Teams = []sciolyff_models.School{old.Teams[1], old.Teams[0]}
+	if got := diff.Compare(old, reordered); len(got) != 0 {
+		t.Errorf("reordering the results changed them:\n%s", lines(got))
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"slices"
+
+	"github.com/Nydauron/avocado2sciolyff/writers"
+	"github.com/urfave/cli/v2"
+)
+
+// Saves the results page at the URL given as the first argument to the file
+// given as the second, or to stdout for "-"
+func fetchHandle(cCtx *cli.Context) error {
+	if cCtx.NArg() != 2 {
+		return fmt.Errorf("expected a URL and a file to save it to, got %d arguments", cCtx.NArg())
+	}
+	pageLocation, outputLocation := cCtx.Args().Get(0), cCtx.Args().Get(1)
+	if u, err := url.ParseRequestURI(pageLocation); err != nil || !slices.Contains([]string{"http", "https"}, u.Scheme) {
+		return fmt.Errorf("%q is not an HTTP URL", pageLocation)
+	}
+
+	page, err := openInput(pageLocation, os.Stderr)
+	if err != nil {
+		return err
+	}
+	defer page.Close()
+
+	var w writers.AbortWriteCloser = writers.NopWriteCloser(os.Stdout)
+	if outputLocation != stdoutCLIName {
+		w = writers.NewAtomicWriteCloser(outputLocation, false)
+	}
+	if _, err := io.Copy(w, page); err != nil {
+		w.Abort()
+		return fmt.Errorf("could not save %s: %w", pageLocation, err)
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	if outputLocation != stdoutCLIName {
+		fmt.Fprintf(os.Stderr, "Saved %s to %s\n", pageLocation, outputLocation)
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ module github.com/Nydauron/avocado2sciolyff
 go 1.23
 
 require (
+	github.com/BurntSushi/toml v1.4.0
 	github.com/urfave/cli/v2 v2.27.4
 	golang.org/x/net v0.22.0
 	gopkg.in/yaml.v3 v3.0.1
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/Nydauron/avocado2sciolyff/parsers"
+	"github.com/urfave/cli/v2"
+)
+
+// Results pages are small, so a request taking longer than this has stalled
+const fetchTimeout = 30 * time.Second
+
+var httpClient = &http.Client{Timeout: fetchTimeout}
+
+// Opens a results page from either a URL or a path to a local file, noting
+// which one it is in logWriter
+func openInput(location string, logWriter io.Writer) (io.ReadCloser, error) {
+	// Absolute paths are also valid request URIs, but have no scheme
+	if u, err := url.ParseRequestURI(location); err == nil && u.Scheme != "" {
+		if !slices.Contains([]string{"http", "https"}, u.Scheme) {
+			return nil, fmt.Errorf("URL is not of HTTP schema (got %q instead)", u.Scheme)
+		}
+		fmt.Fprintln(logWriter, "URL detected")
+		rawURL := u.String()
+		resp, err := httpClient.Get(rawURL)
+		if err != nil {
+			fmt.Fprintf(logWriter, "Error occurred when trying to fetch page: %v\n", err)
+			return nil, err
+		}
+
+		if resp.StatusCode >= 400 {
+			resp.Body.Close()
+			return nil, fmt.Errorf("invalid HTTP status code received: %v", resp.Status)
+		}
+		contentType := resp.Header.Get("content-type")
+		expectedContent := "text/html; charset=UTF-8"
+		if contentType != expectedContent {
+			fmt.Fprintf(logWriter, "Page content recieved is not text/html UTF-8. Got instead %q\n", contentType)
+		}
+		return resp.Body, nil
+	} else if f, err := os.Open(location); err == nil {
+		fmt.Fprintln(logWriter, "File detected")
+		return f, nil
+	}
+	return nil, fmt.Errorf("provided input was neither a valid URL or a path to existing file: %v", location)
+}
+
+// Reads and parses the results table at location, which is a CSV file rather
+// than an HTML page if isCSVFile is set
+func readTable(location string, isCSVFile bool, logWriter io.Writer) (*parsers.Table, error) {
+	r, err := openInput(location, logWriter)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	var table *parsers.Table
+	if isCSVFile {
+		table, err = parsers.ParseCSV(r)
+	} else {
+		table, err = parsers.ParseHTML(r)
+	}
+	if err != nil {
+		return nil, cli.Exit(fmt.Sprintf("cell did not contain number: %v", err), 4)
+	}
+	return table, nil
+}
+
+// Infers tournament metadata from where a table was read from (the URL slug,
+// or the file name for local files) and the page it was parsed from
+func inferMetadata(location string, table *parsers.Table) parsers.InferredMetadata {
+	inferred := parsers.InferredMetadata{}
+	if u, err := url.ParseRequestURI(location); err == nil && slices.Contains([]string{"http", "https"}, u.Scheme) {
+		inferred = parsers.InferMetadataFromURL(u)
+	}
+	inferred.Merge(parsers.InferMetadata(table.Title))
+	inferred.Merge(parsers.InferMetadata(table.Heading))
+	if _, err := os.Stat(location); err == nil {
+		inferred.Merge(parsers.InferMetadataFromFileName(filepath.Base(location)))
+	}
+	return inferred
+}
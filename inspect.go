@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Nydauron/avocado2sciolyff/parsers"
+	"github.com/urfave/cli/v2"
+)
+
+// Prints the table parsed from the results page given as an argument, along
+// with the tournament details inferred from it, to check what a conversion
+// would start from
+func inspectHandle(cCtx *cli.Context) error {
+	if cCtx.NArg() != 1 {
+		return fmt.Errorf("expected a URL or file to inspect, got %d arguments", cCtx.NArg())
+	}
+	location := cCtx.Args().First()
+	table, err := readTable(location, cCtx.Bool(csvFlag), os.Stderr)
+	if err != nil {
+		return err
+	}
+	printTable(table, inferMetadata(location, table))
+	return nil
+}
+
+func printTable(table *parsers.Table, inferred parsers.InferredMetadata) {
+	fmt.Printf("Title: %s\n", table.Title)
+	fmt.Printf("Heading: %s\n", table.Heading)
+	fmt.Println("Inferred:")
+	for _, detail := range []struct{ name, value string }{
+		{"name", inferred.Name},
+		{"state", inferred.State},
+		{"level", inferred.Level},
+		{"division", inferred.Division},
+		{"year", strconv.Itoa(inferred.Year)},
+		{"date", inferred.Date},
+	} {
+		if detail.value != "" && detail.value != "0" {
+			fmt.Printf("  %s: %s\n", detail.name, detail.value)
+		}
+	}
+	fmt.Printf("%d events, %d teams\n\n", len(table.Events), len(table.Schools))
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	header := []string{"Team", "School", "Track"}
+	for _, event := range table.Events {
+		name := event.Name
+		if event.IsMarkedAsTrial {
+			name += " (trial)"
+		}
+		header = append(header, name)
+	}
+	header = append(header, "Total", "Rank")
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+	for _, school := range table.Schools {
+		row := []string{strconv.FormatUint(uint64(school.TeamNumber), 10), school.Name, school.Track}
+		for _, score := range school.Scores {
+			row = append(row, strconv.FormatUint(uint64(score), 10))
+		}
+		row = append(row, school.TotalScore, school.Rank)
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	w.Flush()
+}
@@ -0,0 +1,277 @@
+package metadata
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/Nydauron/avocado2sciolyff/parsers"
+	"github.com/Nydauron/avocado2sciolyff/prompts"
+	"github.com/Nydauron/avocado2sciolyff/sciolyff"
+	"gopkg.in/yaml.v3"
+)
+
+// Editor used when $EDITOR is not set
+const defaultEditor = "vi"
+
+// Prefix of the lines listing validation errors at the top of the template
+const errorCommentPrefix = "# Error: "
+
+const templateHeader = `# Tournament metadata for avocado2sciolyff. Fill in or correct the values
+# below, then save and close the editor to continue the conversion. Values were
+# filled in from the results page and the defaults the prompts would offer.
+# Medals, trophies and bids that are left out default to those usual for the
+# tournament level. Scoring settings that are left out are detected from the
+# scores, and events left empty are prompted for.
+# Emptying the file cancels the conversion.
+`
+
+// Template builds a metadata file with every prompted value filled in, from
+// the options already set, what was inferred from the input and the defaults
+// the prompts would offer. Awards are only filled in when preset, as their
+// defaults depend on the level, which may still be edited; see
+// `FillAwardDefaults`. Scoring settings that could not be detected and events
+// that could not be classified are left for the conversion to settle.
+func Template(table parsers.Table, groupTable *parsers.Table, opts sciolyff.Options) File {
+	t := Tournament{
+		Name:      valueOr(opts.Name, opts.Inferred.Name),
+		ShortName: valueOr(opts.ShortName, ""),
+		Location:  valueOr(opts.Location, ""),
+		Level:     valueOr(opts.Level, opts.Inferred.Level),
+		State:     valueOr(opts.State, opts.Inferred.State),
+		Division:  valueOr(opts.Division, opts.Inferred.Division),
+		Date:      valueOr(opts.Date, opts.Inferred.Date),
+	}
+	t.EndDate = valueOr(opts.EndDate, *t.Date)
+	t.AwardsDate = opts.AwardsDate
+	t.TestDate = opts.TestDate
+	// The year in the tournament name is a calendar year, so the season of
+	// the date is a better guess at the rules year whenever there is a date
+	defaultYear := opts.Inferred.Year
+	if seasonYear, ok := prompts.SeasonRulesYear(*t.Date); ok {
+		defaultYear = seasonYear
+	}
+	t.Year = valueOr(opts.Year, defaultYear)
+
+	t.Medals = opts.Medals
+	t.Trophies = opts.Trophies
+	t.Bids = opts.Bids
+	t.BidsPerSchool = opts.BidsPerSchool
+	t.WorstPlacingsDropped = opts.WorstPlacingsDropped
+	if worstPlacingsDropped, ok := sciolyff.DetectWorstPlacingsDropped(table); ok && t.WorstPlacingsDropped == nil {
+		t.WorstPlacingsDropped = &worstPlacingsDropped
+	}
+	scoring, isScoringKnown := sciolyff.DetectScoringModel(table)
+	if opts.Scoring != nil {
+		scoring, isScoringKnown = *opts.Scoring, true
+	}
+	if isScoringKnown {
+		perEventN := scoring.PerEventN
+		if perEventN == sciolyff.PerEventNNone {
+			perEventN = "none"
+		}
+		t.PerEventN = &perEventN
+		t.NOffset = &scoring.NOffset
+	}
+
+	f := File{Tournament: t, Tracks: map[string]Track{}, Events: map[string]string{}}
+
+	for trackName := range teamCountPerTrack(table) {
+		trackAwards := opts.TrackAwards[trackName]
+		f.Tracks[trackName] = Track{Medals: trackAwards.Medals, Trophies: trackAwards.Trophies}
+	}
+
+	for _, event := range table.Events {
+		if !event.IsMarkedAsTrial {
+			continue
+		}
+		f.Events[event.Name] = EventUndecided
+		isTrial, ok := opts.TrialEvents[event.Name]
+		if !ok && opts.Catalog != nil {
+			if division, hasDivision := opts.Catalog.Division(*t.Year, *t.Division); hasDivision {
+				canonicalName, _ := opts.Catalog.CanonicalName(event.Name)
+				isTrial, ok = !division.IsOfficial(canonicalName), true
+			}
+		}
+		switch {
+		case ok && isTrial:
+			f.Events[event.Name] = EventTrial
+		case ok:
+			f.Events[event.Name] = EventTrialed
+		}
+	}
+
+	if groupTable == nil {
+		f.CalculateTrackPlaces = valueOr(opts.CalculateTrackPlaces, false)
+	}
+	return f
+}
+
+// FillAwardDefaults sets the medals, trophies and bids left out of the file,
+// overall and for every track in the table, to the defaults for the
+// tournament level in the file
+func (f *File) FillAwardDefaults(table parsers.Table) {
+	t := &f.Tournament
+	level := ""
+	if t.Level != nil {
+		level, _ = prompts.ParseLevel(*t.Level)
+	}
+	defaultAwards := sciolyff.DefaultAwards(level, len(table.Schools))
+	t.Medals = valueOr(t.Medals, defaultAwards.Medals)
+	t.Trophies = valueOr(t.Trophies, defaultAwards.Trophies)
+	if sciolyff.LevelHasBids(level) {
+		t.Bids = valueOr(t.Bids, defaultAwards.Bids)
+		t.BidsPerSchool = valueOr(t.BidsPerSchool, defaultAwards.BidsPerSchool)
+	}
+	if f.Tracks == nil {
+		f.Tracks = map[string]Track{}
+	}
+	for trackName, teamCount := range teamCountPerTrack(table) {
+		defaultTrackAwards := sciolyff.DefaultAwards(level, teamCount)
+		track := f.Tracks[trackName]
+		f.Tracks[trackName] = Track{
+			Medals:   valueOr(track.Medals, defaultTrackAwards.Medals),
+			Trophies: valueOr(track.Trophies, defaultTrackAwards.Trophies),
+		}
+	}
+}
+
+// Counts the teams in each named track of the table
+func teamCountPerTrack(table parsers.Table) map[string]int {
+	counts := map[string]int{}
+	for _, team := range table.Schools {
+		if team.Track != "" {
+			counts[team.Track]++
+		}
+	}
+	return counts
+}
+
+// Returns a copy of the preset value if one was given, otherwise the default
+func valueOr[T any](preset *T, defaultValue T) *T {
+	if preset != nil {
+		value := *preset
+		return &value
+	}
+	return &defaultValue
+}
+
+// Validate checks that every required tournament detail is filled in and that
+// every value passes the same checks as its prompt. The state is optional for
+// nationals.
+func (f *File) Validate() error {
+	t := f.Tournament
+	errs := []error{}
+	level := ""
+	if t.Level != nil {
+		level, _ = prompts.ParseLevel(*t.Level)
+	}
+	for _, field := range []struct {
+		name       string
+		value      *string
+		isRequired bool
+	}{
+		{"name", t.Name, true},
+		{"location", t.Location, true},
+		{"level", t.Level, true},
+		{"state", t.State, level != "Nationals"},
+		{"division", t.Division, true},
+		{"date", t.Date, true},
+	} {
+		if field.isRequired && (field.value == nil || strings.TrimSpace(*field.value) == "") {
+			errs = append(errs, fmt.Errorf("tournament %s: an answer is required", field.name))
+		}
+	}
+	if t.Year == nil || *t.Year == 0 {
+		errs = append(errs, fmt.Errorf("tournament year: an answer is required"))
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	return f.Apply(&sciolyff.Options{})
+}
+
+// Edit writes the file as a YAML template, opens it in $EDITOR and reads back
+// the result. The editor is opened again, with the errors listed at the top,
+// until the result is valid. The editor runs on in and out, the same terminal
+// the prompts use.
+func Edit(template File, in io.Reader, out io.Writer) (*File, error) {
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = defaultEditor
+	}
+	editorArgs := strings.Fields(editor)
+
+	templateFile, err := os.CreateTemp("", "avocado2sciolyff-*.yaml")
+	if err != nil {
+		return nil, err
+	}
+	templatePath := templateFile.Name()
+	templateFile.Close()
+	defer os.Remove(templatePath)
+
+	contents := bytes.Buffer{}
+	contents.WriteString(templateHeader)
+	yamlEncoder := yaml.NewEncoder(&contents)
+	yamlEncoder.SetIndent(2)
+	if err := yamlEncoder.Encode(&template); err != nil {
+		return nil, err
+	}
+	if err := yamlEncoder.Close(); err != nil {
+		return nil, err
+	}
+
+	for {
+		if err := os.WriteFile(templatePath, contents.Bytes(), 0600); err != nil {
+			return nil, err
+		}
+		if err := runEditor(editorArgs, templatePath, in, out); err != nil {
+			return nil, fmt.Errorf("editor %q failed: %w", editor, err)
+		}
+
+		edited, err := os.ReadFile(templatePath)
+		if err != nil {
+			return nil, err
+		}
+		edited = withoutErrorComments(edited)
+		if len(bytes.TrimSpace(edited)) == 0 {
+			return nil, fmt.Errorf("metadata template was emptied, cancelling the conversion")
+		}
+
+		f, err := decode(edited, false)
+		if err == nil {
+			err = f.Validate()
+		}
+		if err == nil {
+			return f, nil
+		}
+		fmt.Fprintf(out, "Invalid metadata: %v\n", err)
+		contents.Reset()
+		for _, line := range strings.Split(err.Error(), "\n") {
+			contents.WriteString(errorCommentPrefix + line + "\n")
+		}
+		contents.Write(edited)
+	}
+}
+
+// Opens path in the editor, reading from in and writing to out rather than
+// stdout, which may be carrying the results
+func runEditor(editorArgs []string, path string, in io.Reader, out io.Writer) error {
+	cmd := exec.Command(editorArgs[0], append(editorArgs[1:], path)...)
+	cmd.Stdin = in
+	cmd.Stdout = out
+	cmd.Stderr = out
+	return cmd.Run()
+}
+
+// Drops the error lines added to the top of the template on a previous attempt
+func withoutErrorComments(contents []byte) []byte {
+	for bytes.HasPrefix(contents, []byte(errorCommentPrefix)) {
+		_, contents, _ = bytes.Cut(contents, []byte("\n"))
+	}
+	return contents
+}
@@ -0,0 +1,93 @@
+package metadata_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/Nydauron/avocado2sciolyff/metadata"
+	"github.com/Nydauron/avocado2sciolyff/parsers"
+	"github.com/Nydauron/avocado2sciolyff/sciolyff"
+	sciolyff_models "github.com/Nydauron/avocado2sciolyff/sciolyff/models"
+)
+
+var naperville = parsers.Table{
+	Events: []parsers.AvogadroEvent{{Name: "Codebusters"}, {Name: "Wind Power", IsMarkedAsTrial: true}},
+	Schools: []sciolyff_models.School{
+		{TeamNumber: 1, Name: "Troy HS", Track: "Varsity", Scores: []uint{1, 2}, TotalScore: "1"},
+		{TeamNumber: 2, Name: "Lincoln HS", Track: "JV", Scores: []uint{2, 1}, TotalScore: "2"},
+	},
+}
+
+func TestTemplate(t *testing.T) {
+	inferred := parsers.InferredMetadata{Name: "Naperville Invitational", Level: "Invitational", State: "IL", Division: "C", Date: "2024-02-03"}
+	f := metadata.Template(naperville, nil, sciolyff.Options{Inferred: inferred})
+	tm := f.Tournament
+	if *tm.Name != "Naperville Invitational" || *tm.EndDate != "2024-02-03" || *tm.Year != 2024 || *tm.Location != "" {
+		t.Errorf("got name %q, end date %q, year %d and location %q", *tm.Name, *tm.EndDate, *tm.Year, *tm.Location)
+	}
+	// Awards wait for the level to be final
+	if tm.Medals != nil || f.Tracks["Varsity"].Medals != nil {
+		t.Error("awards were filled in before the level was edited")
+	}
+	if *tm.WorstPlacingsDropped != 0 || f.Events["Wind Power"] != metadata.EventUndecided {
+		t.Errorf("got %d worst placings dropped and Wind Power %q", *tm.WorstPlacingsDropped, f.Events["Wind Power"])
+	}
+
+	f.FillAwardDefaults(naperville)
+	if *f.Tournament.Medals != 2 || *f.Tracks["JV"].Trophies != 1 || f.Tournament.Bids != nil {
+		t.Errorf("got %d medals, %d JV trophies and bids %v", *f.Tournament.Medals, *f.Tracks["JV"].Trophies, f.Tournament.Bids)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr string
+	}{
+		{"complete", "name: MIT\nlocation: MIT\nlevel: i\nstate: MA\ndivision: C\ndate: 2024-01-27\nyear: 2024", ""},
+		{"nationals without a state", "name: Nationals\nlocation: Lehigh\nlevel: n\ndivision: C\ndate: 2024-05-25\nyear: 2024", ""},
+		{"states without a state", "name: Illinois\nlocation: UIUC\nlevel: s\ndivision: C\ndate: 2024-04-20\nyear: 2024", "tournament state: an answer is required"},
+		{"nothing filled in", "", "tournament name: an answer is required\ntournament location: an answer is required"},
+		{"invalid division", "name: MIT\nlocation: MIT\nlevel: i\nstate: MA\ndivision: D\ndate: 2024-01-27\nyear: 2024", `tournament division: "D" is not a division`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := metadata.Load(writeFile(t, "metadata.yaml", "tournament:\n  "+strings.ReplaceAll(tt.yaml, "\n", "\n  ")+"\n"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = f.Validate()
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("got %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.HasPrefix(err.Error(), tt.wantErr)) {
+				t.Errorf("got %v, want an error starting with %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEdit(t *testing.T) {
+	// The "editor" fills in the location, the only value missing
+	script := writeFile(t, "editor.sh", `sed -i 's/location:.*/location: Naperville North HS/' "$1"`)
+	t.Setenv("EDITOR", "sh "+script)
+	inferred := parsers.InferredMetadata{Name: "Naperville Invitational", Level: "Invitational", State: "IL", Division: "C", Date: "2024-02-03"}
+	editorOutput := strings.Builder{}
+	f, err := metadata.Edit(metadata.Template(naperville, nil, sciolyff.Options{Inferred: inferred}), strings.NewReader(""), &editorOutput)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *f.Tournament.Location != "Naperville North HS" {
+		t.Errorf("got location %q", *f.Tournament.Location)
+	}
+	if editorOutput.Len() != 0 {
+		t.Errorf("a valid edit reported %q", editorOutput.String())
+	}
+
+	t.Setenv("EDITOR", "truncate -s 0")
+	if _, err := metadata.Edit(metadata.Template(naperville, nil, sciolyff.Options{}), strings.NewReader(""), io.Discard); err == nil {
+		t.Error("emptying the template did not cancel")
+	}
+}
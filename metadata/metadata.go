@@ -0,0 +1,229 @@
+package metadata
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/Nydauron/avocado2sciolyff/prompts"
+	"github.com/Nydauron/avocado2sciolyff/sciolyff"
+	"gopkg.in/yaml.v3"
+)
+
+// Values for an event in `File.Events`
+const (
+	EventTrial   = "trial"
+	EventTrialed = "trialed"
+	// Left to the event catalog or a prompt to decide
+	EventUndecided = ""
+)
+
+// File holds the answers to the conversion prompts ahead of time. Every field
+// is optional; anything missing is prompted for.
+type File struct {
+	Tournament Tournament `yaml:"tournament" toml:"tournament"`
+	// Per-track awards by track name
+	Tracks map[string]Track `yaml:"tracks,omitempty" toml:"tracks,omitempty"`
+	// Whether each event with a trial marker was a trial event ("trial") or a
+	// regular event that was trialed ("trialed"), by event name. Empty if
+	// undecided.
+	Events map[string]string `yaml:"events,omitempty" toml:"events,omitempty"`
+	// Whether track places are calculated from the overall results when no
+	// groups table is given
+	CalculateTrackPlaces *bool `yaml:"calculate track places,omitempty" toml:"calculate track places,omitempty"`
+}
+
+// Tournament mirrors `sciolyff_models.TournamentMetadata`, with every field
+// optional
+type Tournament struct {
+	Name                 *string `yaml:"name,omitempty" toml:"name,omitempty"`
+	ShortName            *string `yaml:"short name,omitempty" toml:"short name,omitempty"`
+	Location             *string `yaml:"location,omitempty" toml:"location,omitempty"`
+	Level                *string `yaml:"level,omitempty" toml:"level,omitempty"`
+	State                *string `yaml:"state,omitempty" toml:"state,omitempty"`
+	Division             *string `yaml:"division,omitempty" toml:"division,omitempty"`
+	Year                 *int    `yaml:"year,omitempty" toml:"year,omitempty"`
+	Date                 *string `yaml:"date,omitempty" toml:"date,omitempty"`
+	EndDate              *string `yaml:"end date,omitempty" toml:"end date,omitempty"`
+	AwardsDate           *string `yaml:"awards date,omitempty" toml:"awards date,omitempty"`
+	TestDate             *string `yaml:"test date,omitempty" toml:"test date,omitempty"`
+	Medals               *int    `yaml:"medals,omitempty" toml:"medals,omitempty"`
+	Trophies             *int    `yaml:"trophies,omitempty" toml:"trophies,omitempty"`
+	Bids                 *int    `yaml:"bids,omitempty" toml:"bids,omitempty"`
+	BidsPerSchool        *int    `yaml:"bids per school,omitempty" toml:"bids per school,omitempty"`
+	WorstPlacingsDropped *int    `yaml:"worst placings dropped,omitempty" toml:"worst placings dropped,omitempty"`
+	NOffset              *int    `yaml:"n offset,omitempty" toml:"n offset,omitempty"`
+	PerEventN            *string `yaml:"per-event n,omitempty" toml:"per-event n,omitempty"`
+}
+
+type Track struct {
+	Medals   *int `yaml:"medals,omitempty" toml:"medals,omitempty"`
+	Trophies *int `yaml:"trophies,omitempty" toml:"trophies,omitempty"`
+}
+
+// Reads a metadata file. Files ending in ".toml" are read as TOML, everything
+// else as YAML.
+func Load(path string) (*File, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := decode(contents, strings.EqualFold(filepath.Ext(path), ".toml"))
+	if err != nil {
+		return nil, fmt.Errorf("could not read metadata file %s: %w", path, err)
+	}
+	return f, nil
+}
+
+// Decodes a metadata file, rejecting keys that are unknown or nested in the
+// wrong place rather than silently dropping their values
+func decode(contents []byte, isTOML bool) (*File, error) {
+	f := File{}
+	if isTOML {
+		md, err := toml.Decode(string(contents), &f)
+		if err != nil {
+			return nil, err
+		}
+		if undecoded := md.Undecoded(); len(undecoded) > 0 {
+			keys := []string{}
+			for _, key := range undecoded {
+				keys = append(keys, strconv.Quote(key.String()))
+			}
+			return nil, fmt.Errorf("unknown keys %s", strings.Join(keys, ", "))
+		}
+		return &f, nil
+	}
+	yamlDecoder := yaml.NewDecoder(bytes.NewReader(contents))
+	yamlDecoder.KnownFields(true)
+	if err := yamlDecoder.Decode(&f); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	return &f, nil
+}
+
+// Apply fills every option not already set with the values from the file,
+// validating them the same way their prompts do
+func (f *File) Apply(opts *sciolyff.Options) error {
+	t := f.Tournament
+	for _, field := range []struct {
+		name   string
+		value  *string
+		option **string
+		parse  func(string) (string, error)
+	}{
+		{"name", t.Name, &opts.Name, nil},
+		{"short name", t.ShortName, &opts.ShortName, nil},
+		{"location", t.Location, &opts.Location, nil},
+		{"level", t.Level, &opts.Level, prompts.ParseLevel},
+		{"state", t.State, &opts.State, prompts.ParseState},
+		{"division", t.Division, &opts.Division, prompts.ParseDivision},
+		{"date", t.Date, &opts.Date, prompts.ParseTournamentDate},
+		{"end date", t.EndDate, &opts.EndDate, prompts.ParseTournamentDate},
+		{"awards date", t.AwardsDate, &opts.AwardsDate, prompts.ParseTournamentDate},
+		{"test date", t.TestDate, &opts.TestDate, prompts.ParseTournamentDate},
+	} {
+		if field.value == nil || *field.option != nil {
+			continue
+		}
+		value := *field.value
+		if field.parse != nil {
+			var err error
+			if value, err = field.parse(value); err != nil {
+				return fmt.Errorf("tournament %s: %w", field.name, err)
+			}
+		}
+		*field.option = &value
+	}
+	if opts.Date != nil && opts.EndDate != nil {
+		if err := prompts.ValidateDateRange(*opts.Date, *opts.EndDate); err != nil {
+			return fmt.Errorf("tournament end date: %w", err)
+		}
+	}
+
+	for _, field := range []struct {
+		name   string
+		value  *int
+		option **int
+	}{
+		{"year", t.Year, &opts.Year},
+		{"medals", t.Medals, &opts.Medals},
+		{"trophies", t.Trophies, &opts.Trophies},
+		{"bids", t.Bids, &opts.Bids},
+		{"bids per school", t.BidsPerSchool, &opts.BidsPerSchool},
+		{"worst placings dropped", t.WorstPlacingsDropped, &opts.WorstPlacingsDropped},
+	} {
+		if field.value == nil || *field.option != nil {
+			continue
+		}
+		if *field.value < 0 {
+			return fmt.Errorf("tournament %s: must not be negative", field.name)
+		}
+		value := *field.value
+		*field.option = &value
+	}
+
+	if opts.Scoring == nil && (t.PerEventN != nil || t.NOffset != nil) {
+		scoring := sciolyff.ScoringModel{}
+		if t.PerEventN != nil {
+			perEventN, err := sciolyff.ParsePerEventN(*t.PerEventN)
+			if err != nil {
+				return fmt.Errorf("tournament per-event n: %w", err)
+			}
+			scoring.PerEventN = perEventN
+		}
+		if t.NOffset != nil {
+			scoring.NOffset = *t.NOffset
+		}
+		if scoring.NOffset < 0 {
+			return fmt.Errorf("tournament n offset: must not be negative")
+		}
+		opts.Scoring = &scoring
+	}
+
+	for eventName, kind := range f.Events {
+		if _, ok := opts.TrialEvents[eventName]; ok {
+			continue
+		}
+		if opts.TrialEvents == nil {
+			opts.TrialEvents = map[string]bool{}
+		}
+		switch kind {
+		case EventUndecided:
+		case EventTrial:
+			opts.TrialEvents[eventName] = true
+		case EventTrialed:
+			opts.TrialEvents[eventName] = false
+		default:
+			return fmt.Errorf("event %s: %q is not %q or %q", eventName, kind, EventTrial, EventTrialed)
+		}
+	}
+
+	for trackName, track := range f.Tracks {
+		if opts.TrackAwards == nil {
+			opts.TrackAwards = map[string]sciolyff.TrackAwards{}
+		}
+		if (track.Medals != nil && *track.Medals < 0) || (track.Trophies != nil && *track.Trophies < 0) {
+			return fmt.Errorf("track %s: awards must not be negative", trackName)
+		}
+		trackAwards := opts.TrackAwards[trackName]
+		if trackAwards.Medals == nil && track.Medals != nil {
+			trackAwards.Medals = track.Medals
+		}
+		if trackAwards.Trophies == nil && track.Trophies != nil {
+			trackAwards.Trophies = track.Trophies
+		}
+		opts.TrackAwards[trackName] = trackAwards
+	}
+
+	if opts.CalculateTrackPlaces == nil {
+		opts.CalculateTrackPlaces = f.CalculateTrackPlaces
+	}
+	return nil
+}
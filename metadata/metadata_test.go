@@ -0,0 +1,105 @@
+package metadata_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Nydauron/avocado2sciolyff/metadata"
+	"github.com/Nydauron/avocado2sciolyff/sciolyff"
+)
+
+// Writes contents to a file of the given name in a temporary directory
+func writeFile(t *testing.T, name string, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadAndApply(t *testing.T) {
+	yamlPath := writeFile(t, "naperville.yaml", `tournament:
+  name: Naperville Invitational
+  level: i
+  state: Illinois
+  date: February 3, 2024
+  medals: 5
+  per-event n: participation
+events:
+  Wind Power: trial
+tracks:
+  Varsity:
+    trophies: 2
+`)
+	tomlPath := writeFile(t, "naperville.toml", `events = { "Wind Power" = "trial" }
+
+[tournament]
+name = "Naperville Invitational"
+level = "i"
+state = "Illinois"
+date = "February 3, 2024"
+medals = 5
+"per-event n" = "participation"
+
+[tracks.Varsity]
+trophies = 2
+`)
+	for _, path := range []string{yamlPath, tomlPath} {
+		f, err := metadata.Load(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		name := "Naperville Invitational (given)"
+		opts := sciolyff.Options{Name: &name}
+		if err := f.Apply(&opts); err != nil {
+			t.Fatalf("applying %s: %v", filepath.Base(path), err)
+		}
+		if *opts.Name != name || *opts.Level != "Invitational" || *opts.State != "IL" || *opts.Date != "2024-02-03" || *opts.Medals != 5 {
+			t.Errorf("%s: got name %q, level %q, state %q, date %q and %d medals", filepath.Base(path), *opts.Name, *opts.Level, *opts.State, *opts.Date, *opts.Medals)
+		}
+		if *opts.Scoring != (sciolyff.ScoringModel{PerEventN: sciolyff.PerEventNParticipation}) || !opts.TrialEvents["Wind Power"] || *opts.TrackAwards["Varsity"].Trophies != 2 {
+			t.Errorf("%s: got scoring %+v, trial events %v and track awards %v", filepath.Base(path), *opts.Scoring, opts.TrialEvents, opts.TrackAwards)
+		}
+	}
+}
+
+func TestLoadRejectsUnknownKeys(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+		wantErr  string
+	}{
+		{"flat.yaml", "name: Naperville Invitational\nlocation: Naperville North HS\n", "field name not found"},
+		{"misspelled.yaml", "tournament:\n  medal: 5\n", "field medal not found"},
+		{"flat.toml", "name = \"Naperville Invitational\"\n", `unknown keys "name"`},
+		{"nested.toml", "[tracks.Varsity]\nmedal = 5\n", `unknown keys "tracks.Varsity.medal"`},
+	}
+	for _, tt := range tests {
+		_, err := metadata.Load(writeFile(t, tt.name, tt.contents))
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: got %v, want an error containing %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestApplyRejectsInvalidValues(t *testing.T) {
+	tests := map[string]string{
+		"tournament:\n  level: x\n":                                 "tournament level:",
+		"tournament:\n  date: 2024-02-03\n  end date: 2024-02-02\n": "tournament end date:",
+		"tournament:\n  trophies: -1\n":                             "tournament trophies: must not be negative",
+		"events:\n  Wind Power: maybe\n":                            `event Wind Power: "maybe" is not "trial" or "trialed"`,
+		"tournament:\n  per-event n: teams\n":                       "tournament per-event n:",
+	}
+	for contents, wantErr := range tests {
+		f, err := metadata.Load(writeFile(t, "metadata.yaml", contents))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := f.Apply(&sciolyff.Options{}); err == nil || !strings.HasPrefix(err.Error(), wantErr) {
+			t.Errorf("got %v for %q, want an error starting with %q", err, contents, wantErr)
+		}
+	}
+}
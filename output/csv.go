@@ -0,0 +1,56 @@
+package output
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+
+	sciolyff_models "github.com/Nydauron/avocado2sciolyff/sciolyff/models"
+)
+
+// Columns of the long-format CSV, named after their sciolyff keys
+var csvHeader = []string{
+	"event", "trial", "trialed",
+	"team", "school", "track",
+	"participated", "disqualified", "exempt", "unknown",
+	"place", "track place", "tie",
+}
+
+// Writes one row per placing, joined with the attributes of its team and
+// event. Places that were not given are left empty.
+func encodeCSV(w io.Writer, s sciolyff_models.SciolyFF) error {
+	events := map[string]sciolyff_models.Event{}
+	for _, event := range s.Events {
+		events[event.Name] = event
+	}
+	teams := map[uint]sciolyff_models.School{}
+	for _, team := range s.Teams {
+		teams[team.TeamNumber] = team
+	}
+
+	csvWriter := csv.NewWriter(w)
+	if err := csvWriter.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, p := range s.Placings {
+		event, team := events[p.Event], teams[p.TeamNumber]
+		err := csvWriter.Write([]string{
+			p.Event, strconv.FormatBool(event.IsTrial), strconv.FormatBool(event.TrialedNormalEvent),
+			strconv.FormatUint(uint64(p.TeamNumber), 10), team.Name, team.Track,
+			strconv.FormatBool(p.Participated), strconv.FormatBool(p.EventDQ), strconv.FormatBool(p.Exempt), strconv.FormatBool(p.Unknown),
+			formatPlace(p.Place), formatPlace(p.TrackPlace), strconv.FormatBool(p.Tie),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
+
+func formatPlace(place uint) string {
+	if place == 0 {
+		return ""
+	}
+	return strconv.FormatUint(uint64(place), 10)
+}
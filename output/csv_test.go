@@ -0,0 +1,47 @@
+package output_test
+
+import (
+	"encoding/csv"
+	"strings"
+	"testing"
+
+	"github.com/Nydauron/avocado2sciolyff/output"
+	sciolyff_models "github.com/Nydauron/avocado2sciolyff/sciolyff/models"
+)
+
+func TestCSV(t *testing.T) {
+	s := sciolyff_models.SciolyFF{
+		Events: []sciolyff_models.Event{{Name: "Codebusters"}, {Name: "Wind Power", IsTrial: true}},
+		Teams:  []sciolyff_models.School{{TeamNumber: 1, Name: "Troy HS, Michigan", Track: "V"}},
+		Placings: []sciolyff_models.Placing{
+			{Event: "Codebusters", TeamNumber: 1, Participated: true, Place: 3, TrackPlace: 1, Tie: true},
+			{Event: "Wind Power", TeamNumber: 1},
+		},
+	}
+	encoded := strings.Builder{}
+	if err := output.Encode(&encoded, s, output.FormatCSV, "test", []string{"placing: Wind Power, team 1, no-show"}); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(strings.NewReader(encoded.String())).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		{"event", "trial", "trialed", "team", "school", "track", "participated", "disqualified", "exempt", "unknown", "place", "track place", "tie"},
+		{"Codebusters", "false", "false", "1", "Troy HS, Michigan", "V", "true", "false", "false", "false", "3", "1", "true"},
+		// A no-show has no place
+		{"Wind Power", "true", "false", "1", "Troy HS, Michigan", "V", "false", "false", "false", "false", "", "", "false"},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("got %d rows, want %d:\n%s", len(rows), len(want), encoded.String())
+	}
+	for i := range want {
+		if strings.Join(rows[i], ",") != strings.Join(want[i], ",") {
+			t.Errorf("row %d is %q, want %q", i, rows[i], want[i])
+		}
+	}
+
+	if _, err := output.Decode(strings.NewReader(encoded.String()), output.FormatCSV); err == nil {
+		t.Error("a CSV file was read back as results")
+	}
+}
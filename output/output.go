@@ -0,0 +1,227 @@
+package output
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/Nydauron/avocado2sciolyff/sciolyff"
+	sciolyff_models "github.com/Nydauron/avocado2sciolyff/sciolyff/models"
+	"gopkg.in/yaml.v3"
+)
+
+// Format is an encoding the sciolyff results can be written in
+type Format string
+
+const (
+	FormatYAML Format = "yaml"
+	FormatJSON Format = "json"
+	// One row per placing, for analysis. Cannot be read back.
+	FormatCSV Format = "csv"
+	// Human-readable reports, which cannot be read back
+	FormatMarkdown Format = "md"
+	FormatHTML     Format = "html"
+)
+
+func ParseFormat(input string) (Format, error) {
+	format := Format(strings.ToLower(strings.TrimSpace(input)))
+	switch format {
+	case FormatYAML, FormatJSON, FormatCSV, FormatMarkdown, FormatHTML:
+		return format, nil
+	case "yml":
+		return FormatYAML, nil
+	case "markdown":
+		return FormatMarkdown, nil
+	case "htm":
+		return FormatHTML, nil
+	}
+	return "", fmt.Errorf("%q is not an output format (%s, %s, %s, %s or %s)", input, FormatYAML, FormatJSON, FormatCSV, FormatMarkdown, FormatHTML)
+}
+
+// Picks the format matching the extension of an output file, defaulting to
+// YAML
+func FormatForPath(path string) Format {
+	if format, err := ParseFormat(strings.TrimPrefix(filepath.Ext(path), ".")); err == nil {
+		return format
+	}
+	return FormatYAML
+}
+
+// JSON has no comments, so the note on what generated the file and the
+// corrections applied are kept in "//" keys placed before the sciolyff sections
+type jsonDocument struct {
+	Comment     string   `json:"//"`
+	Corrections []string `json:"// corrections,omitempty"`
+	sciolyff_models.SciolyFF
+}
+
+// What each format holds, for the note on what generated it
+var formatDescriptions = map[Format]string{
+	FormatYAML:     "YAML file",
+	FormatJSON:     "JSON file",
+	FormatCSV:      "CSV file",
+	FormatMarkdown: "Markdown report",
+	FormatHTML:     "HTML report",
+}
+
+// Encode writes the results in the given format, noting which version of
+// avocado2sciolyff generated them and the corrections applied to them. CSV
+// files have no room for either note.
+func Encode(w io.Writer, sciolyffDump sciolyff_models.SciolyFF, format Format, version string, corrections []string) error {
+	generatedBy := fmt.Sprintf("This %s was auto-generated by avocado2sciolyff %s", formatDescriptions[format], version)
+	switch format {
+	case FormatCSV:
+		return encodeCSV(w, sciolyffDump)
+	case FormatMarkdown:
+		return encodeMarkdown(w, newReport(sciolyffDump, generatedBy, corrections))
+	case FormatHTML:
+		return encodeHTML(w, newReport(sciolyffDump, generatedBy, corrections))
+	case FormatJSON:
+		jsonEncoder := json.NewEncoder(w)
+		jsonEncoder.SetIndent("", "  ")
+		return jsonEncoder.Encode(jsonDocument{Comment: generatedBy, Corrections: corrections, SciolyFF: sciolyffDump})
+	case FormatYAML:
+		header := "###\n# " + generatedBy + "\n"
+		if len(corrections) > 0 {
+			header += "#\n# Corrections applied:\n"
+			for _, correction := range corrections {
+				header += "#   - " + correction + "\n"
+			}
+		}
+		if _, err := io.WriteString(w, header+"###\n"); err != nil {
+			return err
+		}
+		yamlEncoder := yaml.NewEncoder(w)
+		yamlEncoder.SetIndent(2)
+		if err := yamlEncoder.Encode(&sciolyffDump); err != nil {
+			return err
+		}
+		return yamlEncoder.Close()
+	}
+	return fmt.Errorf("unknown output format %q", format)
+}
+
+// Decode reads results written in the given format
+func Decode(r io.Reader, format Format) (sciolyff_models.SciolyFF, error) {
+	sciolyffDump := sciolyff_models.SciolyFF{}
+	var err error
+	switch format {
+	case FormatJSON:
+		err = json.NewDecoder(r).Decode(&sciolyffDump)
+	case FormatYAML:
+		err = yaml.NewDecoder(r).Decode(&sciolyffDump)
+	case FormatCSV, FormatMarkdown, FormatHTML:
+		err = fmt.Errorf("%s files cannot be read back as results", format)
+	default:
+		err = fmt.Errorf("unknown output format %q", format)
+	}
+	return sciolyffDump, err
+}
+
+// Load reads a results file, in the format matching its extension
+func Load(path string) (sciolyff_models.SciolyFF, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return sciolyff_models.SciolyFF{}, err
+	}
+	defer f.Close()
+	sciolyffDump, err := Decode(f, FormatForPath(path))
+	if err != nil {
+		return sciolyff_models.SciolyFF{}, fmt.Errorf("could not read %s: %w", path, err)
+	}
+	return sciolyffDump, nil
+}
+
+// Level names as they appear in Duosmium file names
+var fileNameLevels = map[string]string{
+	"Invitational": "invitational",
+	"Regionals":    "regional",
+	"States":       "states",
+	"Nationals":    "nationals",
+}
+
+var nonAlphanumericRegex = regexp.MustCompile(`[^a-z0-9]+`)
+
+// FileName names a results file after Duosmium's convention,
+// `YYYY-MM-DD_STATE_name_level_div`, e.g. `2024-04-20_IL_uiuc_states_c.yaml`.
+// The name is the short name if there is one. There is only one tournament of
+// each division per state at the state level and per season at the national
+// level, so states leave out the name (`2024-04-20_IL_states_c`) and nationals
+// both the name and the state (`2024-05-25_nationals_c`). Superscored results
+// keep a `superscore` name to set them apart. Parts that are not known are
+// left out.
+func FileName(t sciolyff_models.TournamentMetadata, format Format) string {
+	name := t.ShortName
+	if name == "" {
+		name = t.Name
+	}
+	state := t.State
+	switch t.Level {
+	case "States", "Nationals":
+		name = ""
+		if strings.HasSuffix(t.Name, sciolyff.SuperscoreNameSuffix) {
+			name = "superscore"
+		}
+		if t.Level == "Nationals" {
+			state = ""
+		}
+	}
+	parts := []string{}
+	for _, part := range []string{
+		t.Date,
+		state,
+		strings.Trim(nonAlphanumericRegex.ReplaceAllString(strings.ToLower(name), "_"), "_"),
+		fileNameLevels[t.Level],
+		strings.ToLower(t.Division),
+	} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, "_") + "." + string(format)
+}
+
+// IsDirectory reports whether results written to location go in a file named
+// after the tournament, as location is an existing directory or ends with a
+// path separator
+func IsDirectory(location string) bool {
+	if strings.HasSuffix(location, "/") || strings.HasSuffix(location, string(filepath.Separator)) {
+		return true
+	}
+	info, err := os.Stat(location)
+	return err == nil && info.IsDir()
+}
+
+// CheckCollision makes sure writing results to path does not replace the
+// results of a different tournament that was given the same file name.
+// Replacing an earlier conversion of the same tournament is allowed.
+func CheckCollision(path string, sciolyffDump sciolyff_models.SciolyFF) error {
+	if format := FormatForPath(path); format != FormatYAML && format != FormatJSON {
+		// Only results files can be read back, so the names of other files
+		// have to tell them apart
+		return nil
+	}
+	existing, err := Load(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("%s already exists and is not a results file: %w", path, err)
+	}
+	e, t := existing.Tournament, sciolyffDump.Tournament
+	if e.Name != t.Name || e.Date != t.Date || e.Division != t.Division {
+		err := fmt.Errorf("%s already holds the results of %s (%s, Division %s)", path, e.Name, e.Date, e.Division)
+		// State and national file names do not have the name in them
+		if t.Level != "States" && t.Level != "Nationals" {
+			err = fmt.Errorf("%w. Give this tournament a different short name to tell them apart", err)
+		}
+		return err
+	}
+	return nil
+}
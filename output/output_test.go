@@ -0,0 +1,82 @@
+package output_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Nydauron/avocado2sciolyff/output"
+	"github.com/Nydauron/avocado2sciolyff/sciolyff"
+	sciolyff_models "github.com/Nydauron/avocado2sciolyff/sciolyff/models"
+)
+
+func TestEncodeLeavesOutEmptyTracks(t *testing.T) {
+	results := sciolyff_models.SciolyFF{
+		Teams: []sciolyff_models.School{{TeamNumber: 1, Name: "Troy HS"}, {TeamNumber: 2, Name: "Lincoln HS", Track: "JV"}},
+	}
+	for _, format := range []output.Format{output.FormatYAML, output.FormatJSON} {
+		encoded := strings.Builder{}
+		if err := output.Encode(&encoded, results, format, "test", nil); err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.Count(encoded.String(), "track"); got != 1 {
+			t.Errorf("the %s file has %d tracks, want only Lincoln HS's:\n%s", format, got, encoded.String())
+		}
+		decoded, err := output.Decode(strings.NewReader(encoded.String()), format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if decoded.Teams[0].HasEmptyTrack() || decoded.Teams[1].Track != "JV" {
+			t.Errorf("the %s file read back as %+v", format, decoded.Teams)
+		}
+	}
+}
+
+func TestFileName(t *testing.T) {
+	tests := []struct {
+		tournament sciolyff_models.TournamentMetadata
+		want       string
+	}{
+		{sciolyff_models.TournamentMetadata{Name: "Naperville North Invitational", ShortName: "Naperville North", Level: "Invitational", State: "IL", Division: "C", Date: "2024-02-03"}, "2024-02-03_IL_naperville_north_invitational_c.yaml"},
+		{sciolyff_models.TournamentMetadata{Name: "Illinois State Tournament", Level: "States", State: "IL", Division: "C", Date: "2024-04-20"}, "2024-04-20_IL_states_c.yaml"},
+		{sciolyff_models.TournamentMetadata{Name: "Illinois State Tournament" + sciolyff.SuperscoreNameSuffix, Level: "States", State: "IL", Division: "C", Date: "2024-04-20"}, "2024-04-20_IL_superscore_states_c.yaml"},
+		{sciolyff_models.TournamentMetadata{Name: "National Tournament", Level: "Nationals", Division: "B", Date: "2024-05-25"}, "2024-05-25_nationals_b.yaml"},
+		{sciolyff_models.TournamentMetadata{Name: "MIT Invitational!"}, "mit_invitational.yaml"},
+	}
+	for _, tt := range tests {
+		if got := output.FileName(tt.tournament, output.FormatYAML); got != tt.want {
+			t.Errorf("FileName(%s) = %q, want %q", tt.tournament.Name, got, tt.want)
+		}
+	}
+}
+
+func TestCheckCollision(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "2024-02-03_IL_naperville_invitational_c.json")
+	naperville := sciolyff_models.SciolyFF{Tournament: sciolyff_models.TournamentMetadata{Name: "Naperville Invitational", Level: "Invitational", Division: "C", Date: "2024-02-03"}}
+	if err := output.CheckCollision(path, naperville); err != nil {
+		t.Errorf("got %v for a new file", err)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if err := output.Encode(f, naperville, output.FormatJSON, "test", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := output.CheckCollision(path, naperville); err != nil {
+		t.Errorf("got %v for a conversion of the same tournament", err)
+	}
+	other := naperville
+	other.Tournament.Name = "Naperville Central Invitational"
+	err = output.CheckCollision(path, other)
+	if err == nil || !strings.Contains(err.Error(), "different short name") {
+		t.Errorf("got %v for a different invitational", err)
+	}
+	other.Tournament.Level = "States"
+	if err := output.CheckCollision(path, other); err == nil || strings.Contains(err.Error(), "short name") {
+		t.Errorf("got %v for a state tournament, whose name is not in its file name", err)
+	}
+}
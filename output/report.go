@@ -0,0 +1,240 @@
+package output
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/Nydauron/avocado2sciolyff/sciolyff"
+	sciolyff_models "github.com/Nydauron/avocado2sciolyff/sciolyff/models"
+)
+
+// A results grid with a row per team and a column per event, ready to be
+// rendered
+type report struct {
+	Title       string
+	Details     []string
+	GeneratedBy string
+	Corrections []string
+	HasTracks   bool
+	Events      []reportEvent
+	Rows        []reportRow
+	Legend      string
+}
+
+type reportEvent struct {
+	Name string
+	// Trial and trialed events are listed after the counted events
+	IsSetApart bool
+}
+
+type reportRow struct {
+	Rank   uint
+	Number uint
+	School string
+	Track  string
+	Cells  []reportCell
+	Total  uint
+}
+
+type reportCell struct {
+	Text      string
+	IsDropped bool
+}
+
+const reportLegend = "Places are followed by the track place in parentheses. " +
+	"T: tied, P: participation points, NS: no-show, DQ: disqualified, EX: exempt, ?: unknown. " +
+	"Dropped placings are struck through. Trial and trialed events do not count towards totals."
+
+func newReport(s sciolyff_models.SciolyFF, generatedBy string, corrections []string) report {
+	t := s.Tournament
+	r := report{
+		Title:       t.Name,
+		GeneratedBy: generatedBy,
+		Corrections: corrections,
+		HasTracks:   len(s.Tracks) > 0,
+		Legend:      reportLegend,
+	}
+	division, rules := "", ""
+	if t.Division != "" {
+		division = "Division " + t.Division
+	}
+	if t.Year != 0 {
+		rules = fmt.Sprintf("%d rules", t.Year)
+	}
+	for _, detail := range []string{t.Location, strings.TrimSpace(t.State + " " + t.Level), division, rules, t.Date} {
+		if detail != "" {
+			r.Details = append(r.Details, detail)
+		}
+	}
+
+	// Names of the events in column order
+	eventNames := []string{}
+	for _, isSetApart := range []bool{false, true} {
+		for _, event := range s.Events {
+			if (event.IsTrial || event.TrialedNormalEvent) == isSetApart {
+				r.Events = append(r.Events, reportEvent{Name: eventLabel(event), IsSetApart: isSetApart})
+				eventNames = append(eventNames, event.Name)
+			}
+		}
+	}
+
+	placings := map[uint]map[string]sciolyff_models.Placing{}
+	for _, p := range s.Placings {
+		if placings[p.TeamNumber] == nil {
+			placings[p.TeamNumber] = map[string]sciolyff_models.Placing{}
+		}
+		placings[p.TeamNumber][p.Event] = p
+	}
+	for _, result := range sciolyff.ScoreResults(s) {
+		row := reportRow{Rank: result.Rank, Number: result.Team.TeamNumber, School: result.Team.Name, Track: result.Team.Track, Total: result.Total}
+		for _, eventName := range eventNames {
+			p, ok := placings[result.Team.TeamNumber][eventName]
+			cell := reportCell{IsDropped: result.Dropped[eventName]}
+			if ok {
+				cell.Text = placingLabel(p)
+			}
+			row.Cells = append(row.Cells, cell)
+		}
+		r.Rows = append(r.Rows, row)
+	}
+	return r
+}
+
+func eventLabel(event sciolyff_models.Event) string {
+	switch {
+	case event.IsTrial:
+		return event.Name + " (trial)"
+	case event.TrialedNormalEvent:
+		return event.Name + " (trialed)"
+	}
+	return event.Name
+}
+
+func placingLabel(p sciolyff_models.Placing) string {
+	switch {
+	case p.Exempt:
+		return "EX"
+	case p.Unknown:
+		return "?"
+	case p.EventDQ:
+		return "DQ"
+	case !p.Participated:
+		return "NS"
+	case p.Place == 0:
+		return "P"
+	}
+	label := strconv.FormatUint(uint64(p.Place), 10)
+	if p.Tie {
+		label += "T"
+	}
+	if p.TrackPlace != 0 {
+		label += fmt.Sprintf(" (%d)", p.TrackPlace)
+	}
+	return label
+}
+
+// Escapes the characters that would break out of a Markdown table cell
+var markdownEscaper = strings.NewReplacer("|", `\|`, "*", `\*`, "_", `\_`, "~", `\~`)
+
+func encodeMarkdown(w io.Writer, r report) error {
+	b := strings.Builder{}
+	fmt.Fprintf(&b, "# %s\n\n", markdownEscaper.Replace(r.Title))
+	if len(r.Details) > 0 {
+		fmt.Fprintf(&b, "%s\n\n", markdownEscaper.Replace(strings.Join(r.Details, " · ")))
+	}
+
+	header := []string{"Rank", "Team", "School"}
+	if r.HasTracks {
+		header = append(header, "Track")
+	}
+	for _, event := range r.Events {
+		name := markdownEscaper.Replace(event.Name)
+		if event.IsSetApart {
+			name = "*" + name + "*"
+		}
+		header = append(header, name)
+	}
+	header = append(header, "Total")
+	fmt.Fprintf(&b, "| %s |\n", strings.Join(header, " | "))
+	fmt.Fprintf(&b, "|%s\n", strings.Repeat(" --- |", len(header)))
+
+	for _, row := range r.Rows {
+		cells := []string{strconv.FormatUint(uint64(row.Rank), 10), strconv.FormatUint(uint64(row.Number), 10), markdownEscaper.Replace(row.School)}
+		if r.HasTracks {
+			cells = append(cells, markdownEscaper.Replace(row.Track))
+		}
+		for _, cell := range row.Cells {
+			text := cell.Text
+			if cell.IsDropped {
+				text = "~~" + text + "~~"
+			}
+			cells = append(cells, text)
+		}
+		cells = append(cells, strconv.FormatUint(uint64(row.Total), 10))
+		fmt.Fprintf(&b, "| %s |\n", strings.Join(cells, " | "))
+	}
+
+	fmt.Fprintf(&b, "\n%s\n\n", r.Legend)
+	if len(r.Corrections) > 0 {
+		b.WriteString("## Corrections\n\n")
+		for _, correction := range r.Corrections {
+			fmt.Fprintf(&b, "- %s\n", markdownEscaper.Replace(correction))
+		}
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, "<!-- %s -->\n", r.GeneratedBy)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+var htmlReport = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="generator" content="{{.GeneratedBy}}">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; font-size: 0.9em; }
+th, td { border: 1px solid #ccc; padding: 0.3em 0.5em; text-align: center; white-space: nowrap; }
+td.school { text-align: left; }
+th.event { writing-mode: vertical-rl; transform: rotate(180deg); }
+.set-apart { background: #f3f3f3; font-style: italic; }
+.dropped { text-decoration: line-through; color: #888; }
+tbody tr:nth-child(even) { background: #fafafa; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+{{if .Details}}<p>{{range $i, $detail := .Details}}{{if $i}} · {{end}}{{$detail}}{{end}}</p>
+{{end}}<table>
+<thead>
+<tr>
+<th>Rank</th><th>Team</th><th>School</th>{{if .HasTracks}}<th>Track</th>{{end}}
+{{range .Events}}<th class="event{{if .IsSetApart}} set-apart{{end}}">{{.Name}}</th>{{end}}
+<th>Total</th>
+</tr>
+</thead>
+<tbody>
+{{range .Rows}}<tr>
+<td>{{.Rank}}</td><td>{{.Number}}</td><td class="school">{{.School}}</td>{{if $.HasTracks}}<td>{{.Track}}</td>{{end}}
+{{range $i, $cell := .Cells}}<td class="{{if (index $.Events $i).IsSetApart}}set-apart{{end}}{{if .IsDropped}} dropped{{end}}">{{.Text}}</td>{{end}}
+<td>{{.Total}}</td>
+</tr>
+{{end}}</tbody>
+</table>
+<p>{{.Legend}}</p>
+{{if .Corrections}}<h2>Corrections</h2>
+<ul>
+{{range .Corrections}}<li>{{.}}</li>
+{{end}}</ul>
+{{end}}</body>
+</html>
+`))
+
+func encodeHTML(w io.Writer, r report) error {
+	return htmlReport.Execute(w, r)
+}
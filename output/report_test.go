@@ -0,0 +1,73 @@
+package output_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Nydauron/avocado2sciolyff/output"
+	sciolyff_models "github.com/Nydauron/avocado2sciolyff/sciolyff/models"
+)
+
+// Two teams in Codebusters, with a trial event and a no-show
+var naperville = sciolyff_models.SciolyFF{
+	Tournament: sciolyff_models.TournamentMetadata{Name: "Naperville Invitational", Location: "Naperville North HS", Level: "Invitational", State: "IL", Division: "C", Year: 2024, Date: "2024-02-03"},
+	Events:     []sciolyff_models.Event{{Name: "Wind Power", IsTrial: true}, {Name: "Codebusters"}},
+	Teams:      []sciolyff_models.School{{TeamNumber: 1, Name: "Troy HS"}, {TeamNumber: 2, Name: "Lincoln_HS"}},
+	Placings: []sciolyff_models.Placing{
+		{Event: "Codebusters", TeamNumber: 1, Participated: true, Place: 2},
+		{Event: "Codebusters", TeamNumber: 2, Participated: true, Place: 1},
+		{Event: "Wind Power", TeamNumber: 1, Participated: true, Place: 1},
+		{Event: "Wind Power", TeamNumber: 2},
+	},
+}
+
+func encodeReport(t *testing.T, s sciolyff_models.SciolyFF, format output.Format, corrections []string) string {
+	t.Helper()
+	b := strings.Builder{}
+	if err := output.Encode(&b, s, format, "test", corrections); err != nil {
+		t.Fatal(err)
+	}
+	return b.String()
+}
+
+func TestMarkdownReport(t *testing.T) {
+	report := encodeReport(t, naperville, output.FormatMarkdown, []string{"placing: Codebusters, team 1, place 2"})
+	for _, want := range []string{
+		"# Naperville Invitational\n\nNaperville North HS · IL Invitational · Division C · 2024 rules · 2024-02-03\n\n",
+		"| Rank | Team | School | Codebusters | *Wind Power (trial)* | Total |\n",
+		// Lincoln won the only counted event, and the trial event is listed last
+		"| 1 | 2 | Lincoln\\_HS | 1 | NS | 1 |\n| 2 | 1 | Troy HS | 2 | 1 | 2 |\n",
+		"## Corrections\n\n- placing: Codebusters, team 1, place 2\n",
+		"<!-- This Markdown report was auto-generated by avocado2sciolyff test -->\n",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("the report is missing %q:\n%s", want, report)
+		}
+	}
+}
+
+func TestReportsLeaveOutMissingDetails(t *testing.T) {
+	undated := naperville
+	undated.Tournament = sciolyff_models.TournamentMetadata{Name: "Naperville Invitational", Level: "Invitational"}
+	if report := encodeReport(t, undated, output.FormatMarkdown, nil); !strings.HasPrefix(report, "# Naperville Invitational\n\nInvitational\n\n|") {
+		t.Errorf("got report:\n%s", report)
+	}
+
+	undated.Tournament.Level = ""
+	report := encodeReport(t, undated, output.FormatHTML, nil)
+	if strings.Contains(report, "Division") || strings.Contains(report, "rules") || strings.Contains(report, "<p></p>") {
+		t.Errorf("got report:\n%s", report)
+	}
+	if strings.Contains(report, "Corrections") {
+		t.Error("a report without corrections lists them")
+	}
+}
+
+func TestHTMLReportEscapesNames(t *testing.T) {
+	escaped := naperville
+	escaped.Teams = []sciolyff_models.School{{TeamNumber: 1, Name: "<b>Troy</b>"}, {TeamNumber: 2, Name: "Lincoln & Oak"}}
+	report := encodeReport(t, escaped, output.FormatHTML, nil)
+	if !strings.Contains(report, "&lt;b&gt;Troy&lt;/b&gt;") || !strings.Contains(report, "Lincoln &amp; Oak") {
+		t.Errorf("school names were not escaped:\n%s", report)
+	}
+}
@@ -11,6 +11,9 @@ var numberRegex = regexp.MustCompile(`[0-9]+`)
 type Table struct {
 	Events  []AvogadroEvent
 	Schools []sciolyff_models.School
+	// Title and first heading of the page the table was parsed from, if any
+	Title   string
+	Heading string
 }
 
 type AvogadroEvent struct {
@@ -26,6 +26,10 @@ func ParseHTML(r io.ReadCloser) (*Table, error) {
 	currentColumn := 0
 	bufferSchool := sciolyff_models.School{}
 	bufferEvent := AvogadroEvent{}
+
+	isTitle := false
+	isHeading := false
+	hasHeading := false
 	for {
 		tt := z.Next()
 		switch tt {
@@ -34,6 +38,12 @@ func ParseHTML(r io.ReadCloser) (*Table, error) {
 		case html.StartTagToken:
 			t := z.Token()
 			switch t.Data {
+			case "title":
+				isTitle = true
+				continue
+			case "h1", "h2":
+				isHeading = !hasHeading && !isTable
+				continue
 			case "span":
 				if isProcessingEventHeader {
 					for _, attr := range t.Attr {
@@ -94,6 +104,14 @@ func ParseHTML(r io.ReadCloser) (*Table, error) {
 		case html.TextToken:
 			t := z.Token()
 			trimmedData := strings.Trim(t.Data, " ")
+			if isTitle {
+				table.Title += t.Data
+				continue
+			}
+			if isHeading {
+				table.Heading += t.Data
+				continue
+			}
 			if isEventName {
 				bufferEvent.Name = trimmedData
 				continue
@@ -130,6 +148,17 @@ func ParseHTML(r io.ReadCloser) (*Table, error) {
 			}
 		case html.EndTagToken:
 			t := z.Token()
+			if t.Data == "title" {
+				isTitle = false
+				table.Title = strings.Join(strings.Fields(table.Title), " ")
+				continue
+			}
+			if (t.Data == "h1" || t.Data == "h2") && isHeading {
+				isHeading = false
+				table.Heading = strings.Join(strings.Fields(table.Heading), " ")
+				hasHeading = table.Heading != ""
+				continue
+			}
 			if t.Data == "a" {
 				isEventName = false
 				continue
@@ -0,0 +1,217 @@
+package parsers
+
+import (
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Nydauron/avocado2sciolyff/prompts"
+)
+
+// InferredMetadata holds tournament details found in a results page or its
+// file name. Empty fields could not be inferred.
+type InferredMetadata struct {
+	Name     string
+	State    string
+	Level    string
+	Division string
+	// Calendar year the tournament name starts with, e.g. 2024 in "2024
+	// Illinois State". This is not necessarily the rules year, which changes
+	// in July.
+	Year int
+	Date string
+}
+
+var (
+	divisionRegex    = regexp.MustCompile(`(?i)\(?\bDiv(?:ision)?\.?\s*([ABC])\b\)?`)
+	leadingYearRegex = regexp.MustCompile(`^((?:19|20)\d{2})\s+`)
+	statePrefixRegex = regexp.MustCompile(`^([A-Za-z .]+?)\s*[:_]\s+`)
+	titleSeparator   = regexp.MustCompile(`\s+[-|]\s+`)
+	isoDateRegex     = regexp.MustCompile(`\b\d{4}-\d{2}-\d{2}\b`)
+	longDateRegex    = regexp.MustCompile(`\b(?:January|February|March|April|May|June|July|August|September|October|November|December) \d{1,2}, \d{4}\b`)
+	levelRegexes     = []struct {
+		regex *regexp.Regexp
+		level string
+	}{
+		{regexp.MustCompile(`(?i)\binvitational\b|\binvite\b`), "Invitational"},
+		{regexp.MustCompile(`(?i)\bregionals?\b`), "Regionals"},
+		{regexp.MustCompile(`(?i)\bstates?\b`), "States"},
+		{regexp.MustCompile(`(?i)\bnationals?\b`), "Nationals"},
+	}
+)
+
+// Page names that can come before the tournament name in a page title,
+// e.g. "Scores by Group - Illinois: ..."
+var pageNames = []string{"scores by group", "results", "overall", "avogadro"}
+
+// InferMetadata extracts whatever tournament details it can find in text such
+// as a page title, page heading or file name, e.g.
+// "2024 University of Illinois Urbana Champaign State (Div. C)"
+func InferMetadata(text string) InferredMetadata {
+	inferred := InferredMetadata{}
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return inferred
+	}
+
+	if date := isoDateRegex.FindString(text); date != "" {
+		if _, err := time.Parse(time.DateOnly, date); err == nil {
+			inferred.Date = date
+		}
+	} else if date := longDateRegex.FindString(text); date != "" {
+		if parsedDate, err := time.Parse("January 2, 2006", date); err == nil {
+			inferred.Date = parsedDate.Format(time.DateOnly)
+		}
+	}
+
+	if match := divisionRegex.FindStringSubmatch(text); match != nil {
+		inferred.Division = strings.ToUpper(match[1])
+		text = divisionRegex.ReplaceAllString(text, "")
+	}
+	text = isoDateRegex.ReplaceAllString(longDateRegex.ReplaceAllString(text, ""), "")
+	name := strings.TrimSpace(tournamentNameSegment(text))
+	if match := leadingYearRegex.FindStringSubmatch(name); match != nil {
+		inferred.Year, _ = strconv.Atoi(match[1])
+		name = name[len(match[0]):]
+	}
+	if match := statePrefixRegex.FindStringSubmatch(name); match != nil {
+		if state, err := prompts.ParseState(match[1]); err == nil {
+			inferred.State = state
+			name = name[len(match[0]):]
+		}
+	}
+	// The year can also follow the state, e.g. "Illinois: 2024 ..."
+	if match := leadingYearRegex.FindStringSubmatch(name); match != nil && inferred.Year == 0 {
+		inferred.Year, _ = strconv.Atoi(match[1])
+		name = name[len(match[0]):]
+	}
+	for _, l := range levelRegexes {
+		if l.regex.MatchString(name) {
+			inferred.Level = l.level
+			break
+		}
+	}
+	// Punctuation that separated a removed date is dropped along with it
+	inferred.Name = strings.Trim(strings.Join(strings.Fields(name), " "), " ,;")
+	return inferred
+}
+
+// InferMetadataFromFileName extracts tournament details from the name of a
+// saved results page or table, e.g. "2024 Illinois State (Div. C).csv". Any
+// file name reads as a tournament name, so the name is only kept when the file
+// name also gives the year, level or division, like an Avogadro page title.
+func InferMetadataFromFileName(fileName string) InferredMetadata {
+	inferred := InferMetadata(strings.TrimSuffix(fileName, filepath.Ext(fileName)))
+	if inferred.Year == 0 && inferred.Level == "" && inferred.Division == "" {
+		inferred.Name = ""
+	}
+	return inferred
+}
+
+// Picks the part of a title naming the tournament, dropping page names and
+// site names separated by " - " or " | "
+func tournamentNameSegment(text string) string {
+	segments := titleSeparator.Split(text, -1)
+	best := ""
+	for _, segment := range segments {
+		isPageName := false
+		for _, pageName := range pageNames {
+			if strings.EqualFold(strings.TrimSpace(segment), pageName) {
+				isPageName = true
+			}
+		}
+		if !isPageName && len(segment) > len(best) {
+			best = segment
+		}
+	}
+	return best
+}
+
+// Merge fills every field not yet inferred with the one from other
+func (m *InferredMetadata) Merge(other InferredMetadata) {
+	if m.Name == "" {
+		m.Name = other.Name
+	}
+	if m.State == "" {
+		m.State = other.State
+	}
+	if m.Level == "" {
+		m.Level = other.Level
+	}
+	if m.Division == "" {
+		m.Division = other.Division
+	}
+	if m.Year == 0 {
+		m.Year = other.Year
+	}
+	if m.Date == "" {
+		m.Date = other.Date
+	}
+}
+
+var (
+	waybackPathRegex     = regexp.MustCompile(`^/web/[0-9]+[a-z_]*/(.+)$`)
+	collapsedSchemeRegex = regexp.MustCompile(`^(https?):/+`)
+	slugLevels           = map[string]string{
+		"invitational": "Invitational",
+		"invite":       "Invitational",
+		"regional":     "Regionals",
+		"regionals":    "Regionals",
+		"state":        "States",
+		"states":       "States",
+		"national":     "Nationals",
+		"nationals":    "Nationals",
+	}
+)
+
+// InferMetadataFromURL extracts the state, level and division from the path of
+// an Avogadro URL such as https://app.avogadro.ws/il/uiuc-state-c/results/overall,
+// also when it is wrapped in a Wayback Machine URL
+func InferMetadataFromURL(u *url.URL) InferredMetadata {
+	inferred := InferredMetadata{}
+	if strings.EqualFold(u.Hostname(), "web.archive.org") {
+		match := waybackPathRegex.FindStringSubmatch(u.Path)
+		if match == nil {
+			return inferred
+		}
+		// The Wayback Machine may collapse the "//" after the scheme
+		archivedURL := collapsedSchemeRegex.ReplaceAllString(match[1], "$1://")
+		if !strings.Contains(archivedURL, "://") {
+			archivedURL = "https://" + archivedURL
+		}
+		archived, err := url.Parse(archivedURL)
+		if err != nil {
+			return inferred
+		}
+		return InferMetadataFromURL(archived)
+	}
+	if !strings.HasSuffix(strings.ToLower(u.Hostname()), "avogadro.ws") {
+		return inferred
+	}
+
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(segments) < 2 {
+		return inferred
+	}
+	if state, err := prompts.ParseState(segments[0]); err == nil {
+		inferred.State = state
+	}
+	slugParts := strings.Split(strings.ToLower(segments[1]), "-")
+	if last := slugParts[len(slugParts)-1]; len(last) == 1 {
+		if division, err := prompts.ParseDivision(last); err == nil {
+			inferred.Division = division
+		}
+	}
+	for _, part := range slugParts {
+		if level, ok := slugLevels[part]; ok && inferred.Level == "" {
+			inferred.Level = level
+		}
+		if match := leadingYearRegex.FindStringSubmatch(part + " "); match != nil && inferred.Year == 0 {
+			inferred.Year, _ = strconv.Atoi(match[1])
+		}
+	}
+	return inferred
+}
@@ -0,0 +1,64 @@
+package parsers_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/Nydauron/avocado2sciolyff/parsers"
+)
+
+func TestInferMetadata(t *testing.T) {
+	tests := map[string]parsers.InferredMetadata{
+		"2024 University of Illinois Urbana Champaign State (Div. C)": {
+			Name: "University of Illinois Urbana Champaign State", Level: "States", Division: "C", Year: 2024,
+		},
+		"Scores by Group - Illinois: 2024 Naperville Invitational Division B": {
+			Name: "Naperville Invitational", Level: "Invitational", State: "IL", Division: "B", Year: 2024,
+		},
+		"Avogadro | 2023 MIT Invite | Results": {Name: "MIT Invite", Level: "Invitational", Year: 2023},
+		"Solon Regional, February 3, 2024":     {Name: "Solon Regional", Level: "Regionals", Date: "2024-02-03"},
+		"Nationals 2024-05-25":                 {Name: "Nationals", Level: "Nationals", Date: "2024-05-25"},
+		"  ":                                   {},
+	}
+	for text, want := range tests {
+		if got := parsers.InferMetadata(text); got != want {
+			t.Errorf("InferMetadata(%q) = %+v, want %+v", text, got, want)
+		}
+	}
+}
+
+func TestInferMetadataFromFileName(t *testing.T) {
+	tests := map[string]parsers.InferredMetadata{
+		"2024 Illinois State (Div. C).csv": {Name: "Illinois State", Level: "States", Division: "C", Year: 2024},
+		"solon-invitational.html":          {Name: "solon-invitational", Level: "Invitational"},
+		"t.csv":                            {},
+		"results.csv":                      {},
+	}
+	for fileName, want := range tests {
+		if got := parsers.InferMetadataFromFileName(fileName); got != want {
+			t.Errorf("InferMetadataFromFileName(%q) = %+v, want %+v", fileName, got, want)
+		}
+	}
+}
+
+func TestInferMetadataFromURL(t *testing.T) {
+	tests := map[string]parsers.InferredMetadata{
+		"https://app.avogadro.ws/il/uiuc-state-c/results/overall":                                    {State: "IL", Level: "States", Division: "C"},
+		"https://app.avogadro.ws/ohio/2024-solon-invitational-b/results/overall":                     {State: "OH", Level: "Invitational", Division: "B", Year: 2024},
+		"https://web.archive.org/web/20240421000000/https://app.avogadro.ws/il/uiuc-state-c/results": {State: "IL", Level: "States", Division: "C"},
+		"https://web.archive.org/web/20240421000000id_/https:/app.avogadro.ws/il/uiuc-state-c":       {State: "IL", Level: "States", Division: "C"},
+		"https://web.archive.org/web/20240421000000/app.avogadro.ws/il/uiuc-state-c":                 {State: "IL", Level: "States", Division: "C"},
+		"https://app.avogadro.ws/il":                      {},
+		"https://example.com/il/uiuc-state-c/results":     {},
+		"https://web.archive.org/save/app.avogadro.ws/il": {},
+	}
+	for location, want := range tests {
+		u, err := url.Parse(location)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := parsers.InferMetadataFromURL(u); got != want {
+			t.Errorf("InferMetadataFromURL(%q) = %+v, want %+v", location, got, want)
+		}
+	}
+}
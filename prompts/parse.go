@@ -0,0 +1,121 @@
+package prompts
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The functions below validate and normalize answers. They are shared by the
+// prompts and anything else that accepts the same values (e.g. metadata files).
+
+// Date formats accepted in answers, tried in order
+var dateLayouts = []string{
+	time.DateOnly,
+	"January 2, 2006",
+	"January 2 2006",
+	"Jan 2, 2006",
+	"Jan 2 2006",
+	"2 January 2006",
+	"1/2/2006",
+	"1/2/06",
+	"1-2-2006",
+}
+
+// Accepts a date in a common format (e.g. "2024-04-20", "April 20, 2024" or
+// "4/20/24") and returns it in the form YYYY-MM-DD
+func ParseTournamentDate(input string) (string, error) {
+	input = strings.TrimSpace(input)
+	for _, layout := range dateLayouts {
+		if date, err := time.Parse(layout, input); err == nil {
+			return date.Format(time.DateOnly), nil
+		}
+	}
+	return "", fmt.Errorf("%q is not a date (e.g. 2024-04-20, April 20, 2024 or 4/20/24)", input)
+}
+
+// Checks that a date range given as YYYY-MM-DD does not end before it starts
+func ValidateDateRange(start string, end string) error {
+	if end < start {
+		return fmt.Errorf("end date %s is before the start date %s", end, start)
+	}
+	return nil
+}
+
+// Returns the rules year of the season a date (YYYY-MM-DD) falls in. Seasons
+// start in the summer, so tournaments from July onwards use the next year's
+// rules.
+func SeasonRulesYear(date string) (int, bool) {
+	parsedDate, err := time.Parse(time.DateOnly, date)
+	if err != nil {
+		return 0, false
+	}
+	if parsedDate.Month() >= time.July {
+		return parsedDate.Year() + 1, true
+	}
+	return parsedDate.Year(), true
+}
+
+func ParseRulesYear(input string) (int, error) {
+	year, err := strconv.Atoi(input)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a year", input)
+	}
+	return year, nil
+}
+
+// Expects a non-negative integer
+func ParseCount(input string) (int, error) {
+	count, err := strconv.Atoi(input)
+	if err != nil || count < 0 {
+		return 0, fmt.Errorf("%q is not a non-negative number", input)
+	}
+	return count, nil
+}
+
+// Accepts the division letter in either case and returns it in upper case
+func ParseDivision(input string) (string, error) {
+	division := strings.ToUpper(strings.TrimSpace(input))
+	if len(division) != 1 || division[0] < 'A' || division[0] > 'C' {
+		return "", fmt.Errorf("%q is not a division (A, B or C)", input)
+	}
+	return division, nil
+}
+
+// Accepts a level or its first letter and returns the full sciolyff level
+func ParseLevel(input string) (string, error) {
+	trimmedInput := strings.ToLower(strings.TrimSpace(input))
+	if trimmedInput == "" {
+		return "", fmt.Errorf("no tournament level given")
+	}
+	level := TranslateLevelAbbrevToFull(trimmedInput[0])
+	if level == "" {
+		return "", fmt.Errorf("%q is not a tournament level (Invitational, Regionals, States or Nationals)", input)
+	}
+	return level, nil
+}
+
+// Accepts a state name or abbreviation and returns the abbreviation
+func ParseState(input string) (string, error) {
+	state := strings.ToUpper(strings.TrimSpace(input))
+	if slices.Contains(stateAbbreviations, state) {
+		return state, nil
+	}
+	if slices.Contains(stateNames, state) {
+		return stateMapping[state], nil
+	}
+	return "", fmt.Errorf("%q is not a state name or abbreviation", input)
+}
+
+// Expects y or n
+func ParseYesNo(input string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(input)) {
+	case "y":
+		return true, nil
+	case "n":
+		return false, nil
+	}
+	return false, fmt.Errorf("%q is not y or n", input)
+}
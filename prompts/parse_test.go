@@ -0,0 +1,49 @@
+package prompts_test
+
+import (
+	"testing"
+
+	"github.com/Nydauron/avocado2sciolyff/prompts"
+)
+
+func TestParseTournamentDate(t *testing.T) {
+	for _, input := range []string{"2024-04-20", "April 20, 2024", "April 20 2024", "Apr 20, 2024", "20 April 2024", "4/20/2024", "4/20/24", "04-20-2024", " 2024-04-20 "} {
+		if got, err := prompts.ParseTournamentDate(input); got != "2024-04-20" || err != nil {
+			t.Errorf("ParseTournamentDate(%q) = %q, %v", input, got, err)
+		}
+	}
+	for _, input := range []string{"", "2024-02-30", "20/4/2024", "next Saturday"} {
+		if got, err := prompts.ParseTournamentDate(input); err == nil {
+			t.Errorf("ParseTournamentDate(%q) = %q, want an error", input, got)
+		}
+	}
+}
+
+func TestValidateDateRange(t *testing.T) {
+	if err := prompts.ValidateDateRange("2024-04-19", "2024-04-20"); err != nil {
+		t.Errorf("a two-day tournament was rejected: %v", err)
+	}
+	if err := prompts.ValidateDateRange("2024-04-20", "2024-04-20"); err != nil {
+		t.Errorf("a one-day tournament was rejected: %v", err)
+	}
+	if err := prompts.ValidateDateRange("2024-04-20", "2024-04-19"); err == nil {
+		t.Error("an end date before the start date was accepted")
+	}
+}
+
+func TestSeasonRulesYear(t *testing.T) {
+	tests := map[string]int{
+		"2024-04-20": 2024,
+		"2024-06-30": 2024,
+		"2024-07-01": 2025,
+		"2023-11-04": 2024,
+	}
+	for date, want := range tests {
+		if got, ok := prompts.SeasonRulesYear(date); got != want || !ok {
+			t.Errorf("SeasonRulesYear(%q) = %d, %t, want %d", date, got, ok, want)
+		}
+	}
+	if _, ok := prompts.SeasonRulesYear("April 2024"); ok {
+		t.Error("a date that is not YYYY-MM-DD has a rules year")
+	}
+}
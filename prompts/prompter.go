@@ -0,0 +1,118 @@
+package prompts
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ErrNoAnswer is returned by a `Prompter` that has no answer to a question
+var ErrNoAnswer = errors.New("no answer available")
+
+// Question is a single prompt put to the user
+type Question struct {
+	// Identifies the question, e.g. "name" or "trial:Codebusters"
+	Key     string
+	Message string
+	// Answer used when an empty answer is given. Empty if there is none.
+	Default string
+	// Whether an empty answer is acceptable even without a default
+	Optional bool
+}
+
+// Prompter answers questions, whether by asking someone or otherwise
+type Prompter interface {
+	// Returns the answer to a question. Empty answers are replaced with the
+	// question's default.
+	Ask(q Question) (string, error)
+	// Called when an answer fails validation. Returning nil asks the question
+	// again, and returning an error gives up on it.
+	Reject(q Question, answer string, reason error) error
+}
+
+// TerminalPrompter asks questions interactively, re-asking until a valid
+// answer is given
+type TerminalPrompter struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+// Creates a new `TerminalPrompter` reading answers from in and writing
+// questions to out. The same reader is kept across questions so no buffered
+// input is lost.
+func NewTerminalPrompter(in io.Reader, out io.Writer) *TerminalPrompter {
+	return &TerminalPrompter{in: bufio.NewReader(in), out: out}
+}
+
+func (t *TerminalPrompter) Ask(q Question) (string, error) {
+	if q.Default != "" {
+		fmt.Fprintf(t.out, "%s [%s] ", q.Message, q.Default)
+	} else {
+		fmt.Fprintf(t.out, "%s ", q.Message)
+	}
+	answer, err := readLine(t.in)
+	if err != nil {
+		return "", fmt.Errorf("%w for %s: %w", ErrNoAnswer, q.Key, err)
+	}
+	if answer == "" {
+		return q.Default, nil
+	}
+	return answer, nil
+}
+
+func (t *TerminalPrompter) Reject(q Question, answer string, reason error) error {
+	fmt.Fprintf(t.out, "Invalid answer: %v\n", reason)
+	return nil
+}
+
+// ScriptedPrompter answers questions in order from a list of answers, one per
+// line. An empty line takes the question's default.
+type ScriptedPrompter struct {
+	in *bufio.Reader
+}
+
+func NewScriptedPrompter(answers io.Reader) *ScriptedPrompter {
+	return &ScriptedPrompter{in: bufio.NewReader(answers)}
+}
+
+func (s *ScriptedPrompter) Ask(q Question) (string, error) {
+	answer, err := readLine(s.in)
+	if err != nil {
+		return "", fmt.Errorf("%w for %s: ran out of scripted answers", ErrNoAnswer, q.Key)
+	}
+	if answer == "" {
+		return q.Default, nil
+	}
+	return answer, nil
+}
+
+func (s *ScriptedPrompter) Reject(q Question, answer string, reason error) error {
+	return fmt.Errorf("scripted answer for %s is invalid: %w", q.Key, reason)
+}
+
+// DefaultsPrompter answers every question with its default and fails on
+// questions without one
+type DefaultsPrompter struct{}
+
+func (DefaultsPrompter) Ask(q Question) (string, error) {
+	if q.Default == "" && !q.Optional {
+		return "", fmt.Errorf("%w for %s: prompting is disabled and there is no default", ErrNoAnswer, q.Key)
+	}
+	return q.Default, nil
+}
+
+func (DefaultsPrompter) Reject(q Question, answer string, reason error) error {
+	return fmt.Errorf("default for %s is invalid: %w", q.Key, reason)
+}
+
+// Reads a single line without its line break. A last line without a line
+// break is still returned; io.EOF is only returned once nothing is left.
+func readLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", err
+	}
+	return strings.TrimRight(line, lineBreak), nil
+}
@@ -0,0 +1,50 @@
+package prompts_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Nydauron/avocado2sciolyff/prompts"
+)
+
+func TestScriptedPrompterAnswersInOrder(t *testing.T) {
+	p := prompts.NewScriptedPrompter(strings.NewReader("Naperville Invitational\n\nIL"))
+	questions := []prompts.Question{
+		{Key: "name"},
+		{Key: "location", Default: "Naperville North HS"},
+		{Key: "state"},
+		{Key: "division"},
+	}
+	want := []string{"Naperville Invitational", "Naperville North HS", "IL"}
+	for i, answer := range want {
+		got, err := p.Ask(questions[i])
+		if err != nil {
+			t.Fatalf("asking %s: %v", questions[i].Key, err)
+		}
+		if got != answer {
+			t.Errorf("asking %s: got %q, want %q", questions[i].Key, got, answer)
+		}
+	}
+
+	_, err := p.Ask(questions[3])
+	if !errors.Is(err, prompts.ErrNoAnswer) || !strings.Contains(err.Error(), "for division: ran out of scripted answers") {
+		t.Errorf("got %v once the answers ran out", err)
+	}
+	if err := p.Reject(questions[0], "x", errors.New("too short")); err == nil {
+		t.Error("rejecting a scripted answer did not give up on the question")
+	}
+}
+
+func TestDefaultsPrompter(t *testing.T) {
+	p := prompts.DefaultsPrompter{}
+	if got, err := p.Ask(prompts.Question{Key: "year", Default: "2024"}); got != "2024" || err != nil {
+		t.Errorf("got %q, %v for a question with a default", got, err)
+	}
+	if got, err := p.Ask(prompts.Question{Key: "short name", Optional: true}); got != "" || err != nil {
+		t.Errorf("got %q, %v for an optional question", got, err)
+	}
+	if _, err := p.Ask(prompts.Question{Key: "location"}); !errors.Is(err, prompts.ErrNoAnswer) {
+		t.Errorf("got %v for a required question without a default", err)
+	}
+}
@@ -1,13 +1,9 @@
 package prompts
 
 import (
-	"bufio"
 	"fmt"
-	"os"
-	"slices"
 	"strconv"
 	"strings"
-	"time"
 )
 
 const (
@@ -15,71 +11,101 @@ const (
 	eventWasTrialed = false
 )
 
-func EventDistingushTrialMarkerPrompt(eventName string) bool {
+// Asks until the answer parses, unless the prompter gives up on the question
+func ask[T any](p Prompter, q Question, parse func(string) (T, error)) (T, error) {
 	for {
-		userInput := Prompt(fmt.Sprintf("Event %s had a trial marker. Was this event a trial event (1) or was the event trialed (2)? ", eventName))
-		if userSelection, err := strconv.ParseInt(userInput, 10, 8); err == nil {
-			switch userSelection {
-			case 1:
-				return eventIsTrial
-			case 2:
-				return eventWasTrialed
-			}
+		answer, err := p.Ask(q)
+		if err != nil {
+			var zero T
+			return zero, err
+		}
+		value, err := parse(answer)
+		if err == nil {
+			return value, nil
+		}
+		if err := p.Reject(q, answer, err); err != nil {
+			var zero T
+			return zero, err
 		}
 	}
 }
 
-func TournamentDatePrompt() string {
-	for {
-		userInput := Prompt("Tournament date: ")
-		_, err := time.Parse(time.DateOnly, userInput)
-		if err == nil {
-			return userInput
+func EventDistingushTrialMarkerPrompt(p Prompter, eventName string) (bool, error) {
+	return ask(p, Question{
+		Key:     "trial:" + eventName,
+		Message: fmt.Sprintf("Event %s had a trial marker. Was this event a trial event (1) or was the event trialed (2)?", eventName),
+	}, func(answer string) (bool, error) {
+		switch strings.TrimSpace(answer) {
+		case "1":
+			return eventIsTrial, nil
+		case "2":
+			return eventWasTrialed, nil
 		}
-	}
+		return false, fmt.Errorf("%q is not 1 or 2", answer)
+	})
 }
 
-func RulesYearPrompt() int {
-	for {
-		userInput := Prompt("Rules Year: ")
-		parsedYear, err := strconv.Atoi(userInput)
-		if err == nil {
-			return parsedYear
+func TextPrompt(p Prompter, key string, message string, defaultText string, isOptional bool) (string, error) {
+	return ask(p, Question{Key: key, Message: message, Default: defaultText, Optional: isOptional}, func(answer string) (string, error) {
+		if answer == "" && !isOptional {
+			return "", fmt.Errorf("an answer is required")
 		}
-	}
+		return answer, nil
+	})
+}
+
+func TournamentDatePrompt(p Prompter, defaultDate string) (string, error) {
+	return ask(p, Question{Key: "date", Message: "Tournament (start) date:", Default: defaultDate}, ParseTournamentDate)
 }
 
-func TournamentDivisionPrompt() string {
-	translatedDivision := ""
-	for translatedDivision == "" {
-		userInput := strings.ToUpper(Prompt("Tournament division (a, b, c): "))
-		if userInput[0] >= 'A' || userInput[0] <= 'C' {
-			translatedDivision = userInput[:1]
+// Defaults to a single day tournament ending on the start date
+func TournamentEndDatePrompt(p Prompter, startDate string) (string, error) {
+	return ask(p, Question{Key: "end date", Message: "Tournament end date:", Default: startDate}, func(answer string) (string, error) {
+		endDate, err := ParseTournamentDate(answer)
+		if err != nil {
+			return "", err
 		}
-	}
-	return translatedDivision
+		if err := ValidateDateRange(startDate, endDate); err != nil {
+			return "", err
+		}
+		return endDate, nil
+	})
 }
 
-func TournamentLevelPrompt() string {
-	translatedLevel := ""
-	for translatedLevel == "" {
-		userInput := Prompt("Tournament level (i, r, s, n): ")
-		translatedLevel = TranslateLevelAbbrevToFull(strings.ToLower(userInput)[0])
+// A default year of 0 means there is no default
+func RulesYearPrompt(p Prompter, defaultYear int) (int, error) {
+	q := Question{Key: "year", Message: "Rules Year:"}
+	if defaultYear != 0 {
+		q.Default = strconv.Itoa(defaultYear)
 	}
-	return translatedLevel
+	return ask(p, q, ParseRulesYear)
 }
 
-func StatePrompt() string {
-	translatedState := ""
-	for translatedState == "" {
-		userInput := strings.ToUpper(Prompt("State: "))
-		if slices.Contains(stateAbbreviations, userInput) {
-			translatedState = userInput
-		} else if slices.Contains(stateNames, userInput) {
-			translatedState = stateMapping[userInput]
-		}
+func CountPrompt(p Prompter, key string, message string, defaultCount int) (int, error) {
+	return ask(p, Question{Key: key, Message: message + ":", Default: strconv.Itoa(defaultCount)}, ParseCount)
+}
+
+func TournamentDivisionPrompt(p Prompter, defaultDivision string) (string, error) {
+	return ask(p, Question{Key: "division", Message: "Tournament division (a, b, c):", Default: defaultDivision}, ParseDivision)
+}
+
+func TournamentLevelPrompt(p Prompter, defaultLevel string) (string, error) {
+	return ask(p, Question{Key: "level", Message: "Tournament level (i, r, s, n):", Default: defaultLevel}, ParseLevel)
+}
+
+// Asks for the state. Nationals have none, so the state can be left empty
+// when isOptional is set.
+func StatePrompt(p Prompter, defaultState string, isOptional bool) (string, error) {
+	message := "State:"
+	if isOptional {
+		message = "State (optional):"
 	}
-	return translatedState
+	return ask(p, Question{Key: "state", Message: message, Default: defaultState, Optional: isOptional}, func(answer string) (string, error) {
+		if answer == "" && isOptional {
+			return "", nil
+		}
+		return ParseState(answer)
+	})
 }
 
 func TranslateLevelAbbrevToFull(a byte) string {
@@ -97,22 +123,14 @@ func TranslateLevelAbbrevToFull(a byte) string {
 	}
 }
 
-func AllowCalculationTrackPlaceFromOverallPrompt() bool {
-	for {
-		userInput := Prompt("Calculate track placements based on overall score? (y/N) ")
-		userInput = strings.ToLower(userInput)
-		if userInput == "y" {
-			return true
-		}
-		if userInput == "n" || userInput == "" {
-			return false
-		}
-	}
+func AllowCalculationTrackPlaceFromOverallPrompt(p Prompter) (bool, error) {
+	return ConfirmPrompt(p, "calculate track places", "Calculate track placements based on overall score?", false)
 }
 
-func Prompt(message string) string {
-	fmt.Fprint(os.Stderr, message)
-	buf := bufio.NewReader(os.Stdin)
-	input, _ := buf.ReadString('\n')
-	return strings.TrimRight(input, lineBreak)
+func ConfirmPrompt(p Prompter, key string, message string, defaultYes bool) (bool, error) {
+	q := Question{Key: key, Message: message + " (y/n)", Default: "n"}
+	if defaultYes {
+		q.Default = "y"
+	}
+	return ask(p, q, ParseYesNo)
 }
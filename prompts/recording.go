@@ -0,0 +1,111 @@
+package prompts
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Answers maps question keys (e.g. "trial:Codebusters") to their answers
+type Answers map[string]string
+
+// Reads an answers file written by `RecordingPrompter.Save`
+func LoadAnswers(path string) (Answers, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	answers := Answers{}
+	if err := yaml.Unmarshal(contents, &answers); err != nil {
+		return nil, fmt.Errorf("could not read answers file %s: %w", path, err)
+	}
+	return answers, nil
+}
+
+// RecordingPrompter keeps every answer accepted from another `Prompter` so
+// that they can be saved and replayed on a later run
+type RecordingPrompter struct {
+	prompter Prompter
+	answers  Answers
+	// Question keys in the order they were first asked
+	keys []string
+}
+
+func NewRecordingPrompter(p Prompter) *RecordingPrompter {
+	return &RecordingPrompter{prompter: p, answers: Answers{}}
+}
+
+func (r *RecordingPrompter) Ask(q Question) (string, error) {
+	answer, err := r.prompter.Ask(q)
+	if err != nil {
+		return "", err
+	}
+	if !slices.Contains(r.keys, q.Key) {
+		r.keys = append(r.keys, q.Key)
+	}
+	r.answers[q.Key] = answer
+	return answer, nil
+}
+
+func (r *RecordingPrompter) Reject(q Question, answer string, reason error) error {
+	delete(r.answers, q.Key)
+	return r.prompter.Reject(q, answer, reason)
+}
+
+// Writes the recorded answers to a YAML file, in the order they were asked
+func (r *RecordingPrompter) Save(path string) error {
+	mapping := yaml.Node{Kind: yaml.MappingNode}
+	for _, key := range r.keys {
+		answer, ok := r.answers[key]
+		if !ok {
+			continue
+		}
+		mapping.Content = append(mapping.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: key},
+			&yaml.Node{Kind: yaml.ScalarNode, Value: answer, Tag: "!!str"},
+		)
+	}
+	contents := bytes.Buffer{}
+	yamlEncoder := yaml.NewEncoder(&contents)
+	yamlEncoder.SetIndent(2)
+	if err := yamlEncoder.Encode(&mapping); err != nil {
+		return err
+	}
+	if err := yamlEncoder.Close(); err != nil {
+		return err
+	}
+	return os.WriteFile(path, contents.Bytes(), 0644)
+}
+
+// ReplayingPrompter answers questions from previously recorded answers, and
+// asks another `Prompter` the questions that have none. A recorded answer that
+// is no longer valid is dropped and the question is asked instead.
+type ReplayingPrompter struct {
+	answers  Answers
+	fallback Prompter
+}
+
+func NewReplayingPrompter(answers Answers, fallback Prompter) *ReplayingPrompter {
+	return &ReplayingPrompter{answers: answers, fallback: fallback}
+}
+
+func (r *ReplayingPrompter) Ask(q Question) (string, error) {
+	if answer, ok := r.answers[q.Key]; ok {
+		if answer == "" {
+			return q.Default, nil
+		}
+		return answer, nil
+	}
+	return r.fallback.Ask(q)
+}
+
+func (r *ReplayingPrompter) Reject(q Question, answer string, reason error) error {
+	if _, ok := r.answers[q.Key]; ok {
+		delete(r.answers, q.Key)
+		return r.fallback.Reject(q, answer, fmt.Errorf("recorded answer is invalid: %w", reason))
+	}
+	return r.fallback.Reject(q, answer, reason)
+}
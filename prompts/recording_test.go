@@ -0,0 +1,67 @@
+package prompts_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Nydauron/avocado2sciolyff/prompts"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "answers.yaml")
+	name := prompts.Question{Key: "name"}
+	year := prompts.Question{Key: "year", Default: "2024"}
+	trial := prompts.Question{Key: "trial:Wind Power"}
+
+	recorder := prompts.NewRecordingPrompter(prompts.NewScriptedPrompter(strings.NewReader("Naperville Invitational\n\nyes\n")))
+	for _, q := range []prompts.Question{name, year, trial} {
+		if _, err := recorder.Ask(q); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The answer to the trial prompt is rejected, so it is not recorded
+	if err := recorder.Reject(trial, "yes", errors.New(`"yes" is not y or n`)); err == nil {
+		t.Fatal("the scripted prompter did not give up on an invalid answer")
+	}
+	if err := recorder.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "name: Naperville Invitational\nyear: \"2024\"\n"; string(contents) != want {
+		t.Errorf("saved %q, want %q", contents, want)
+	}
+
+	answers, err := prompts.LoadAnswers(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replayer := prompts.NewReplayingPrompter(answers, prompts.NewScriptedPrompter(strings.NewReader("y\n")))
+	for q, want := range map[prompts.Question]string{name: "Naperville Invitational", year: "2024", trial: "y"} {
+		if got, err := replayer.Ask(q); got != want || err != nil {
+			t.Errorf("asking %s: got %q, %v, want %q", q.Key, got, err, want)
+		}
+	}
+}
+
+func TestReplayingPrompterDropsInvalidAnswers(t *testing.T) {
+	level := prompts.Question{Key: "level", Default: "Invitational"}
+	replayer := prompts.NewReplayingPrompter(prompts.Answers{"level": "x"}, prompts.NewScriptedPrompter(strings.NewReader("s\n")))
+	answer, err := replayer.Ask(level)
+	if answer != "x" || err != nil {
+		t.Fatalf("got %q, %v for the recorded level", answer, err)
+	}
+	err = replayer.Reject(level, answer, errors.New(`"x" is not a tournament level`))
+	if err == nil || !strings.Contains(err.Error(), "recorded answer is invalid") {
+		t.Errorf("got %v on rejecting a recorded answer", err)
+	}
+	// The question then falls through to the other prompter
+	if answer, err := replayer.Ask(level); answer != "s" || err != nil {
+		t.Errorf("got %q, %v once the recorded answer was dropped", answer, err)
+	}
+}
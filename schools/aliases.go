@@ -0,0 +1,172 @@
+package schools
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"os"
+	"slices"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Minimum similarity for a canonical school name to be suggested for a name
+// with no alias
+const suggestionThreshold = 0.8
+
+// Spelled out forms of abbreviations commonly found in school names
+var abbreviations = map[string]string{
+	"hs":   "high school",
+	"ms":   "middle school",
+	"jhs":  "junior high school",
+	"shs":  "senior high school",
+	"sch":  "school",
+	"acad": "academy",
+	"intl": "international",
+	"jr":   "junior",
+	"sr":   "senior",
+	"mt":   "mount",
+	"ft":   "fort",
+}
+
+// Aliases maps the names schools appear under to their canonical names. It is
+// stored as a YAML file mapping each canonical name to its aliases.
+type Aliases struct {
+	path    string
+	schools map[string][]string
+	// Canonical names by normalized canonical name or alias
+	canonicalNames map[string]string
+	isModified     bool
+}
+
+// Reads the alias file at path. A missing file gives an empty set of aliases,
+// which is created on `Save`.
+func Load(path string) (*Aliases, error) {
+	a := Aliases{path: path, schools: map[string][]string{}, canonicalNames: map[string]string{}}
+	contents, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &a, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(contents, &a.schools); err != nil {
+		return nil, err
+	}
+	for canonicalName, aliases := range a.schools {
+		a.canonicalNames[normalize(canonicalName)] = canonicalName
+		for _, alias := range aliases {
+			a.canonicalNames[normalize(alias)] = canonicalName
+		}
+	}
+	return &a, nil
+}
+
+// Returns the canonical name of a school if the name is a known school or
+// alias, ignoring case, punctuation and common abbreviations
+func (a *Aliases) Canonical(name string) (string, bool) {
+	canonicalName, ok := a.canonicalNames[normalize(name)]
+	return canonicalName, ok
+}
+
+// Suggests the canonical school name most similar to name, if any is similar
+// enough
+func (a *Aliases) Suggest(name string) (string, bool) {
+	normalizedName := normalize(name)
+	bestName := ""
+	bestSimilarity := 0.0
+	for normalizedCanonical, canonicalName := range a.canonicalNames {
+		similarity := similarity(normalizedName, normalizedCanonical)
+		if similarity > bestSimilarity || (similarity == bestSimilarity && canonicalName < bestName) {
+			bestName = canonicalName
+			bestSimilarity = similarity
+		}
+	}
+	return bestName, bestSimilarity >= suggestionThreshold
+}
+
+// Records alias as another name for the school with the given canonical name.
+// Adding a name as its own alias records a new school.
+func (a *Aliases) Add(canonicalName string, alias string) {
+	if _, ok := a.schools[canonicalName]; !ok {
+		a.schools[canonicalName] = []string{}
+	}
+	if alias != canonicalName && !slices.Contains(a.schools[canonicalName], alias) {
+		a.schools[canonicalName] = append(a.schools[canonicalName], alias)
+		slices.Sort(a.schools[canonicalName])
+	}
+	a.canonicalNames[normalize(canonicalName)] = canonicalName
+	a.canonicalNames[normalize(alias)] = canonicalName
+	a.isModified = true
+}
+
+// Writes the aliases back to their file if any were added
+func (a *Aliases) Save() error {
+	if !a.isModified {
+		return nil
+	}
+	contents := bytes.Buffer{}
+	yamlEncoder := yaml.NewEncoder(&contents)
+	yamlEncoder.SetIndent(2)
+	if err := yamlEncoder.Encode(a.schools); err != nil {
+		return err
+	}
+	if err := yamlEncoder.Close(); err != nil {
+		return err
+	}
+	if err := os.WriteFile(a.path, contents.Bytes(), 0644); err != nil {
+		return err
+	}
+	a.isModified = false
+	return nil
+}
+
+// Reduces a school name to lower case words with punctuation removed and
+// abbreviations spelled out
+func normalize(name string) string {
+	name = strings.NewReplacer("&", " and ", ".", "", "'", "", "’", "").Replace(strings.ToLower(name))
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) > 1 && words[0] == "the" {
+		words = words[1:]
+	}
+	for i, word := range words {
+		if spelledOut, ok := abbreviations[word]; ok {
+			words[i] = spelledOut
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// Similarity between two strings from 0 (nothing in common) to 1 (equal),
+// based on their edit distance
+func similarity(a string, b string) float64 {
+	longest := max(len([]rune(a)), len([]rune(b)))
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein([]rune(a), []rune(b)))/float64(longest)
+}
+
+func levenshtein(a []rune, b []rune) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			substitutionCost := 1
+			if a[i-1] == b[j-1] {
+				substitutionCost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+substitutionCost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
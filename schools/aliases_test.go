@@ -0,0 +1,93 @@
+package schools_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Nydauron/avocado2sciolyff/schools"
+)
+
+func loadAliases(t *testing.T, contents string) *schools.Aliases {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "schools.yaml")
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	aliases, err := schools.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return aliases
+}
+
+func TestCanonical(t *testing.T) {
+	aliases := loadAliases(t, "Troy High School:\n  - Troy Senior High\nThe Harker School: []\n")
+	tests := map[string]string{
+		"Troy High School": "Troy High School",
+		"troy h.s.":        "Troy High School",
+		"Troy HS":          "Troy High School",
+		"TROY SENIOR HIGH": "Troy High School",
+		"Harker Sch":       "The Harker School",
+		"Troy Middle":      "",
+		"Lincoln HS":       "",
+	}
+	for name, want := range tests {
+		got, ok := aliases.Canonical(name)
+		if got != want || ok != (want != "") {
+			t.Errorf("Canonical(%q) = %q, %t, want %q", name, got, ok, want)
+		}
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	aliases := loadAliases(t, "Troy High School: []\nAdlai E. Stevenson High School: []\n")
+	tests := map[string]string{
+		// Misspellings are close enough to be suggested
+		"Troy Hgh School":             "Troy High School",
+		"Adlai Stevenson High School": "Adlai E. Stevenson High School",
+		// but other schools are not
+		"Troy Middle School": "",
+		"Novi High School":   "",
+	}
+	for name, want := range tests {
+		got, ok := aliases.Suggest(name)
+		if ok != (want != "") || (ok && got != want) {
+			t.Errorf("Suggest(%q) = %q, %t, want %q", name, got, ok, want)
+		}
+	}
+}
+
+func TestAddAndSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "schools.yaml")
+	aliases, err := schools.Load(path)
+	if err != nil {
+		t.Fatalf("a missing alias file did not load as empty: %v", err)
+	}
+	if err := aliases.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); err == nil {
+		t.Error("saving unchanged aliases wrote the file")
+	}
+
+	aliases.Add("Troy High School", "Troy High School")
+	aliases.Add("Troy High School", "Troy Senior High")
+	if err := aliases.Save(); err != nil {
+		t.Fatal(err)
+	}
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Troy High School:\n  - Troy Senior High\n"; string(contents) != want {
+		t.Errorf("saved %q, want %q", contents, want)
+	}
+	reloaded, err := schools.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := reloaded.Canonical("Troy Senior High"); got != "Troy High School" || !ok {
+		t.Errorf("got %q, %t for a saved alias", got, ok)
+	}
+}
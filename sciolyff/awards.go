@@ -0,0 +1,44 @@
+package sciolyff
+
+// Awards holds the number of medals, trophies and bids handed out either at
+// the tournament level or within a single track.
+type Awards struct {
+	Medals        int
+	Trophies      int
+	Bids          int
+	BidsPerSchool int
+}
+
+// DefaultAwards returns the awards typically handed out at a tournament of the
+// given level with teamCount competing teams. Invitationals and regionals fall
+// back to the same formulas sciolyff uses when the fields are absent, while
+// states and nationals use the usual fixed counts. Medals and trophies are
+// never more than the number of teams.
+func DefaultAwards(level string, teamCount int) Awards {
+	awards := Awards{
+		Medals:   max((teamCount+9)/10, 3),
+		Trophies: max((teamCount+5)/6, 3),
+	}
+	switch level {
+	case "Regionals":
+		awards.Bids = awards.Trophies
+		awards.BidsPerSchool = 1
+	case "States":
+		awards.Medals = 6
+		awards.Trophies = 6
+		awards.Bids = 1
+		awards.BidsPerSchool = 1
+	case "Nationals":
+		awards.Medals = 6
+		awards.Trophies = 10
+	}
+	awards.Medals = min(awards.Medals, teamCount)
+	awards.Trophies = min(awards.Trophies, teamCount)
+	return awards
+}
+
+// LevelHasBids reports whether teams at a tournament of the given level can
+// earn bids to the next level of competition.
+func LevelHasBids(level string) bool {
+	return level == "Regionals" || level == "States"
+}
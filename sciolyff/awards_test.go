@@ -0,0 +1,39 @@
+package sciolyff_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/Nydauron/avocado2sciolyff/prompts"
+	"github.com/Nydauron/avocado2sciolyff/sciolyff"
+)
+
+func TestDefaultAwards(t *testing.T) {
+	tests := []struct {
+		level     string
+		teamCount int
+		want      sciolyff.Awards
+	}{
+		{"Invitational", 45, sciolyff.Awards{Medals: 5, Trophies: 8}},
+		{"Invitational", 12, sciolyff.Awards{Medals: 3, Trophies: 3}},
+		{"Invitational", 2, sciolyff.Awards{Medals: 2, Trophies: 2}},
+		{"Regionals", 30, sciolyff.Awards{Medals: 3, Trophies: 5, Bids: 5, BidsPerSchool: 1}},
+		{"States", 40, sciolyff.Awards{Medals: 6, Trophies: 6, Bids: 1, BidsPerSchool: 1}},
+		{"Nationals", 60, sciolyff.Awards{Medals: 6, Trophies: 10}},
+	}
+	for _, tt := range tests {
+		if got := sciolyff.DefaultAwards(tt.level, tt.teamCount); got != tt.want {
+			t.Errorf("DefaultAwards(%q, %d) = %+v, want %+v", tt.level, tt.teamCount, got, tt.want)
+		}
+	}
+}
+
+func TestGenerateSciolyFFRejectsBidsWithoutBidLevel(t *testing.T) {
+	answers := "Naperville Invitational\n\nNaperville North HS\ni\nIL\nc\n2024-02-03\n\n\n1\nn\n"
+	bids := 2
+	_, err := sciolyff.GenerateSciolyFF(invitational, nil, prompts.NewScriptedPrompter(strings.NewReader(answers)), sciolyff.Options{Bids: &bids, Log: io.Discard})
+	if err == nil || !strings.Contains(err.Error(), "invitational tournaments do not give out bids") {
+		t.Errorf("got %v for bids at an invitational", err)
+	}
+}
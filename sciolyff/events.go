@@ -0,0 +1,79 @@
+package sciolyff
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Nydauron/avocado2sciolyff/catalog"
+	"github.com/Nydauron/avocado2sciolyff/parsers"
+	"github.com/Nydauron/avocado2sciolyff/prompts"
+	sciolyff_models "github.com/Nydauron/avocado2sciolyff/sciolyff/models"
+)
+
+// Names of events in the output, by the name they appear under on Avogadro
+type eventNameMap map[string]string
+
+func (m eventNameMap) canonical(name string) string {
+	if canonical, ok := m[name]; ok {
+		return canonical
+	}
+	return name
+}
+
+// Builds the sciolyff events from the events on Avogadro. With an event
+// catalog, names are normalized and events with a trial marker are classified
+// as trial or trialed using the official events of the tournament's rules year
+// and division. Anything that is neither preset nor settled by the catalog is
+// prompted for.
+func classifyEvents(avogadroEvents []parsers.AvogadroEvent, tournament sciolyff_models.TournamentMetadata, p prompts.Prompter, opts Options) ([]sciolyff_models.Event, eventNameMap, error) {
+	logWriter := opts.LogWriter()
+	var division catalog.Division
+	hasDivision := false
+	// Neither is set when its question went unanswered
+	isTournamentKnown := tournament.Year != 0 && tournament.Division != ""
+	if opts.Catalog != nil && isTournamentKnown {
+		division, hasDivision = opts.Catalog.Division(tournament.Year, tournament.Division)
+		if !hasDivision {
+			fmt.Fprintf(logWriter, "Event catalog has no events for %d Division %s. Skipping event classification ...\n", tournament.Year, tournament.Division)
+		}
+	}
+
+	names := eventNameMap{}
+	unanswered := []error{}
+	events := make([]sciolyff_models.Event, 0, len(avogadroEvents))
+	for _, e := range avogadroEvents {
+		name := e.Name
+		if opts.Catalog != nil {
+			if canonicalName, ok := opts.Catalog.CanonicalName(e.Name); ok && canonicalName != e.Name {
+				fmt.Fprintf(logWriter, "Renaming event %s to %s\n", e.Name, canonicalName)
+				name = canonicalName
+			}
+		}
+		names[e.Name] = name
+		if hasDivision && !e.IsMarkedAsTrial && !division.IsOfficial(name) {
+			fmt.Fprintf(logWriter, "Warning: event %s is not an official event in %d Division %s\n", name, tournament.Year, tournament.Division)
+		}
+
+		isEventTrialEvent := false
+		if e.IsMarkedAsTrial {
+			if isTrial, ok := opts.TrialEvents[e.Name]; ok {
+				isEventTrialEvent = isTrial
+			} else if isTrial, ok := opts.TrialEvents[name]; ok {
+				isEventTrialEvent = isTrial
+			} else if hasDivision {
+				isEventTrialEvent = !division.IsOfficial(name)
+				if !division.IsKnown(name) {
+					fmt.Fprintf(logWriter, "Warning: event %s is not in the event catalog for %d Division %s. Assuming it is a trial event ...\n", name, tournament.Year, tournament.Division)
+				}
+			} else {
+				isTrial, err := prompts.EventDistingushTrialMarkerPrompt(p, e.Name)
+				if err := collectUnanswered(err, &unanswered); err != nil {
+					return nil, nil, err
+				}
+				isEventTrialEvent = isTrial
+			}
+		}
+		events = append(events, sciolyff_models.Event{Name: name, IsTrial: e.IsMarkedAsTrial && isEventTrialEvent, TrialedNormalEvent: e.IsMarkedAsTrial && !isEventTrialEvent})
+	}
+	return events, names, errors.Join(unanswered...)
+}
@@ -1,12 +1,19 @@
 package sciolyff
 
 import (
+	"cmp"
+	"errors"
 	"fmt"
+	"io"
+	"maps"
 	"os"
 	"slices"
+	"strings"
 
+	"github.com/Nydauron/avocado2sciolyff/catalog"
 	"github.com/Nydauron/avocado2sciolyff/parsers"
 	"github.com/Nydauron/avocado2sciolyff/prompts"
+	"github.com/Nydauron/avocado2sciolyff/schools"
 	sciolyff_models "github.com/Nydauron/avocado2sciolyff/sciolyff/models"
 )
 
@@ -16,15 +23,94 @@ const (
 	TrackPlaceProvided = 2
 )
 
-func GenerateSciolyFF(table parsers.Table, groupResTable *parsers.Table) sciolyff_models.SciolyFF {
-	// FIX: Assumes table and groupResTable have the same events and same teams. Should do some validation here or earlier ...
-	events := make([]sciolyff_models.Event, 0)
-	for _, e := range table.Events {
-		isEventTrialEvent := false
-		if e.IsMarkedAsTrial {
-			isEventTrialEvent = prompts.EventDistingushTrialMarkerPrompt(e.Name)
+// Options holds values supplied ahead of time (e.g. through CLI flags or a
+// metadata file). Any field left nil is asked to the `Prompter` instead.
+type Options struct {
+	Name      *string
+	ShortName *string
+	Location  *string
+	Level     *string
+	State     *string
+	Division  *string
+	Year      *int
+	// Start date of the tournament, or its only date
+	Date *string
+	// Last day of a multi-day tournament. Prompted for, defaulting to Date.
+	EndDate *string
+	// Dates of the awards ceremony and of the tests, e.g. for online
+	// tournaments. Never prompted for.
+	AwardsDate *string
+	TestDate   *string
+
+	Medals        *int
+	Trophies      *int
+	Bids          *int
+	BidsPerSchool *int
+	// Awards within each track, by track name
+	TrackAwards map[string]TrackAwards
+	// Number of worst placings dropped from each team's total. Detected from
+	// the reported totals if nil.
+	WorstPlacingsDropped *int
+	// How raw scores translate to places, no-shows and disqualifications.
+	// Detected from the scores if nil.
+	Scoring *ScoringModel
+
+	// Whether each event with a trial marker was a trial event (true) or a
+	// regular event that was trialed (false), by event name
+	TrialEvents map[string]bool
+	// Official events used to normalize event names and classify events with
+	// a trial marker. Skipped if nil.
+	Catalog *catalog.Catalog
+	// Canonical school names that team school names are rewritten to. New
+	// aliases confirmed while prompting are added to it. Skipped if nil.
+	SchoolAliases *schools.Aliases
+	// Whether track places are calculated from the overall results when no
+	// groups table is given
+	CalculateTrackPlaces *bool
+	// Order of the placings in the output, either `PlacingOrderEvent` or
+	// `PlacingOrderTeam`. Defaults to ordering by event.
+	PlacingOrder string
+
+	// Tournament details inferred from the input, offered as prompt defaults
+	Inferred parsers.InferredMetadata
+	// Where warnings and progress notes are written. Defaults to stderr.
+	Log io.Writer
+}
+
+// LogWriter returns where warnings and progress notes are written
+func (opts Options) LogWriter() io.Writer {
+	if opts.Log == nil {
+		return os.Stderr
+	}
+	return opts.Log
+}
+
+type TrackAwards struct {
+	Medals   *int
+	Trophies *int
+}
+
+func GenerateSciolyFF(table parsers.Table, groupResTable *parsers.Table, p prompts.Prompter, opts Options) (sciolyff_models.SciolyFF, error) {
+	logWriter := opts.LogWriter()
+	if err := checkScoreCounts(table); err != nil {
+		return sciolyff_models.SciolyFF{}, err
+	}
+	if groupResTable != nil {
+		if err := checkScoreCounts(*groupResTable); err != nil {
+			return sciolyff_models.SciolyFF{}, fmt.Errorf("groups table: %w", err)
 		}
-		events = append(events, sciolyff_models.Event{Name: e.Name, IsTrial: e.IsMarkedAsTrial && isEventTrialEvent, TrialedNormalEvent: e.IsMarkedAsTrial && !isEventTrialEvent})
+	}
+	// Every question without an answer is reported at once, so that a
+	// metadata file can be completed in one go
+	tournament, tournamentErr := promptTournament(p, opts)
+	if tournamentErr != nil && !errors.Is(tournamentErr, prompts.ErrNoAnswer) {
+		return sciolyff_models.SciolyFF{}, tournamentErr
+	}
+
+	// FIX: Assumes table and groupResTable have the same events and same teams. Should do some validation here or earlier ...
+	events, eventNames, eventsErr := classifyEvents(table.Events, tournament, p, opts)
+	if err := errors.Join(tournamentErr, eventsErr); err != nil {
+		return sciolyff_models.SciolyFF{}, err
 	}
 
 	var isTrackPlaceCalculationAllowed uint
@@ -32,17 +118,23 @@ func GenerateSciolyFF(table parsers.Table, groupResTable *parsers.Table) sciolyf
 	groupScoresByTeam := map[uint]map[string]uint{}
 	if groupResTable != nil {
 		isTrackPlaceCalculationAllowed = TrackPlaceProvided
-		fmt.Fprintln(os.Stderr, "Table with group results was provided. Skipping track place calculation ...")
+		fmt.Fprintln(logWriter, "Table with group results was provided. Skipping track place calculation ...")
 		for _, team := range groupResTable.Schools {
 			// FIX: Assumes order is the same event order as overall
 			scoreMap := map[string]uint{}
 			for i, score := range team.Scores {
-				scoreMap[groupResTable.Events[i].Name] = score
+				scoreMap[eventNames.canonical(groupResTable.Events[i].Name)] = score
 			}
 			groupScoresByTeam[team.TeamNumber] = scoreMap
 		}
 	} else {
-		if prompts.AllowCalculationTrackPlaceFromOverallPrompt() {
+		isCalculationAllowed, err := presetOrPrompt(opts.CalculateTrackPlaces, func() (bool, error) {
+			return prompts.AllowCalculationTrackPlaceFromOverallPrompt(p)
+		})
+		if err != nil {
+			return sciolyff_models.SciolyFF{}, err
+		}
+		if isCalculationAllowed {
 			isTrackPlaceCalculationAllowed = TrackPlaceCalc
 		} else {
 			isTrackPlaceCalculationAllowed = TrackPlaceNoCalc
@@ -51,15 +143,36 @@ func GenerateSciolyFF(table parsers.Table, groupResTable *parsers.Table) sciolyf
 
 	placings := make([]*sciolyff_models.Placing, 0)
 	teamCount := uint(len(table.Schools))
+
+	scoring := ScoringModel{}
+	if opts.Scoring != nil {
+		scoring = *opts.Scoring
+	} else if detectedScoring, ok := DetectScoringModel(table); ok {
+		scoring = detectedScoring
+		if scoring.PerEventN != PerEventNNone || scoring.NOffset != 0 {
+			fmt.Fprintf(logWriter, "Scores match per-event n %q with an n offset of %d\n", scoring.PerEventN, scoring.NOffset)
+		}
+	} else {
+		fmt.Fprintln(logWriter, "Scores do not match any known scoring model. Assuming no-shows score the team count + 1 ...")
+	}
+	// N of each event. Participation-only placings score N, no-shows N+1 and
+	// disqualifications N+2
+	eventNs := make([]uint, len(events))
+	for eventIdx := range events {
+		n, ok := scoring.EventN(teamCount, EventScores(table, eventIdx))
+		if !ok {
+			fmt.Fprintf(logWriter, "Warning: scores for event %s do not match the scoring model\n", events[eventIdx].Name)
+			n = teamCount + uint(scoring.NOffset)
+		}
+		eventNs[eventIdx] = n
+	}
+
 	trackNames := map[string]struct{}{}
 	teamCountPerTrack := map[string]uint{}
 	placingsByEventByTrack := make([]map[string][]*sciolyff_models.Placing, len(events))
+	placingsByEvent := make([][]*sciolyff_models.Placing, len(events))
 	for _, team := range table.Schools {
 		trackNames[team.Track] = struct{}{}
-		if len(events) != len(team.Scores) {
-			panic(fmt.Sprintf("Score array for team \"%s\" is not the same size as number of events (%d events, %d scores)", team.Name, len(events), len(team.Scores)))
-		}
-
 		if _, ok := teamCountPerTrack[team.Track]; !ok {
 			teamCountPerTrack[team.Track] = 0
 		}
@@ -68,18 +181,23 @@ func GenerateSciolyFF(table parsers.Table, groupResTable *parsers.Table) sciolyf
 		for eventIdx, score := range team.Scores {
 			p := sciolyff_models.Placing{Event: events[eventIdx].Name, TeamNumber: team.TeamNumber}
 			p.Participated = true
-			if score >= teamCount+1 { // NS
+			// If a team gets awarded P points Participated must be true and Points must not be set.
+			// Last place scores the same as P points, which is resolved once all scores are read.
+			switch DecodeScore(score, eventNs[eventIdx]) {
+			case ScorePlace:
+				p.Place = score
+			case ScoreNoShow:
+				p.Participated = false
+			case ScoreDisqualified:
+				p.Participated = false
+				p.EventDQ = true
+			case ScoreInvalid:
+				fmt.Fprintf(logWriter, "Warning: score %d of team %d in event %s is above the disqualification score %d. Assuming a disqualification ...\n", score, team.TeamNumber, events[eventIdx].Name, eventNs[eventIdx]+2)
 				p.Participated = false
-			}
-			if score >= teamCount+2 { // DQ
 				p.EventDQ = true
-			}
-			// If a team gets awarded P points Participated must be true and Points must not be set
-			// NOTE: If a single team got last, it is impossible to know whether the team got last or P points
-			if score < teamCount {
-				p.Place = score
 			}
 			placings = append(placings, &p)
+			placingsByEvent[eventIdx] = append(placingsByEvent[eventIdx], &p)
 
 			if placingsByEventByTrack[eventIdx] == nil {
 				placingsByEventByTrack[eventIdx] = make(map[string][]*sciolyff_models.Placing)
@@ -91,80 +209,269 @@ func GenerateSciolyFF(table parsers.Table, groupResTable *parsers.Table) sciolyf
 			placingsByEventByTrack[eventIdx][team.Track] = append(placingsByEventByTrack[eventIdx][team.Track], placings[len(placings)-1])
 		}
 	}
+
+	for eventIdx, event := range events {
+		trackScores := map[uint]trackScore{}
+		if groupResTable != nil {
+			trackScores = eventTrackScores(event.Name, table.Schools, teamCountPerTrack, groupScoresByTeam, scoring)
+		}
+		for _, ambiguity := range resolveLastPlaces(eventNs[eventIdx], scoring, placingsByEvent[eventIdx], EventScores(table, eventIdx), trackScores) {
+			fmt.Fprintf(logWriter, "Warning: %s. Assuming participation points ...\n", ambiguity)
+		}
+	}
+
 	switch isTrackPlaceCalculationAllowed {
 	case TrackPlaceCalc:
 		for _, eventPlacingsByTrack := range placingsByEventByTrack {
-			for track, placings := range eventPlacingsByTrack {
-				slices.SortFunc(placings, func(a, b *sciolyff_models.Placing) int {
-					if !a.EventDQ && b.EventDQ {
-						return -1
-					}
-					if !b.EventDQ && a.EventDQ {
-						return 1
-					}
-					if a.EventDQ && b.EventDQ {
-						return 0
-					}
-					if a.Participated && !b.Participated {
-						return -1
-					}
-					if b.Participated && !a.Participated {
-						return 1
-					}
-					if !a.Participated && !b.Participated {
-						return 0
-					}
-					if a.Place == 0 && b.Place != 0 {
-						return 1
-					}
-					if b.Place == 0 && a.Place != 0 {
-						return -1
-					}
-					return int(a.Place) - int(b.Place)
-				})
-
-				for i, p := range placings {
-					if !p.Participated {
-						continue
-					}
-					if p.Place == teamCount {
-						p.TrackPlace = teamCountPerTrack[track]
-					} else {
-						p.TrackPlace = uint(i + 1)
-					}
+			for trackName, placings := range eventPlacingsByTrack {
+				if trackName == "" {
+					continue
 				}
+				rankTrackPlaces(placings)
 			}
 		}
 	case TrackPlaceProvided:
 		for _, eventPlacingsByTrack := range placingsByEventByTrack {
-			for _, placings := range eventPlacingsByTrack {
+			for trackName, placings := range eventPlacingsByTrack {
+				if trackName == "" {
+					continue
+				}
 				for _, p := range placings {
-					p.TrackPlace = groupScoresByTeam[p.TeamNumber][p.Event]
+					// Only placed teams have a track place; the other scores
+					// are participation points, no-shows or disqualifications
+					if p.Place != 0 {
+						p.TrackPlace = groupScoresByTeam[p.TeamNumber][p.Event]
+					}
 				}
 			}
 		}
 	}
 
+	markTies(placingsByEvent)
+
 	tracks := []sciolyff_models.Track{}
 
 	for trackName := range trackNames {
+		// Teams without a track are only found in tournaments without tracks
+		if trackName == "" {
+			continue
+		}
 		tracks = append(tracks, sciolyff_models.Track{Name: trackName})
 	}
+	// Map order is random, so tracks are sorted to prompt in the same order
+	// every time
+	slices.SortFunc(tracks, func(a, b sciolyff_models.Track) int { return cmp.Compare(a.Name, b.Name) })
 
-	tournament := sciolyff_models.TournamentMetadata{
-		Name:      prompts.Prompt("Tournament name: "),
-		ShortName: prompts.Prompt("Tournament nickname/short name: "),
-		Location:  prompts.Prompt("Tournament location (host building/campus): "),
-		Level:     prompts.TournamentLevelPrompt(),
-		State:     prompts.StatePrompt(),
-		Division:  prompts.TournamentDivisionPrompt(),
-		Year:      prompts.RulesYearPrompt(),
-		Date:      prompts.TournamentDatePrompt(),
+	detectedDropped, isDropDetected := DetectWorstPlacingsDropped(table)
+	switch {
+	case opts.WorstPlacingsDropped != nil:
+		tournament.WorstPlacingsDropped = *opts.WorstPlacingsDropped
+		if isDropDetected && detectedDropped != tournament.WorstPlacingsDropped {
+			fmt.Fprintf(logWriter, "Warning: reported totals match %d worst placings dropped, but %d was given\n", detectedDropped, tournament.WorstPlacingsDropped)
+		}
+	case isDropDetected:
+		if detectedDropped > 0 {
+			fmt.Fprintf(logWriter, "Reported totals match with the %d worst placings dropped\n", detectedDropped)
+		}
+		tournament.WorstPlacingsDropped = detectedDropped
+	default:
+		fmt.Fprintln(logWriter, "Reported totals could not be matched by dropping worst placings")
+		worstPlacingsDropped, err := prompts.CountPrompt(p, "worst placings dropped", "Worst placings dropped", 0)
+		if err != nil {
+			return sciolyff_models.SciolyFF{}, err
+		}
+		tournament.WorstPlacingsDropped = worstPlacingsDropped
+	}
+
+	tournament.NOffset = scoring.NOffset
+	tournament.PerEventN = scoring.PerEventN
+
+	defaultAwards := DefaultAwards(tournament.Level, int(teamCount))
+	counts := []countField{
+		{&tournament.Medals, opts.Medals, "medals", "Medals awarded per event", defaultAwards.Medals},
+		{&tournament.Trophies, opts.Trophies, "trophies", "Trophies awarded", defaultAwards.Trophies},
+	}
+	if !LevelHasBids(tournament.Level) && (opts.Bids != nil || opts.BidsPerSchool != nil) {
+		return sciolyff_models.SciolyFF{}, fmt.Errorf("bids were given, but %s tournaments do not give out bids", strings.ToLower(tournament.Level))
+	}
+	if LevelHasBids(tournament.Level) {
+		counts = append(counts,
+			countField{&tournament.Bids, opts.Bids, "bids", "Bids to the next tournament", defaultAwards.Bids},
+			countField{&tournament.BidsPerSchool, opts.BidsPerSchool, "bids per school", "Maximum bids per school", defaultAwards.BidsPerSchool},
+		)
+	}
+	for i, track := range tracks {
+		if track.Name == "" {
+			continue
+		}
+		defaultTrackAwards := DefaultAwards(tournament.Level, int(teamCountPerTrack[track.Name]))
+		trackAwards := opts.TrackAwards[track.Name]
+		counts = append(counts,
+			countField{&tracks[i].Medals, trackAwards.Medals, "medals:" + track.Name, fmt.Sprintf("Medals awarded per event in track %s", track.Name), defaultTrackAwards.Medals},
+			countField{&tracks[i].Trophies, trackAwards.Trophies, "trophies:" + track.Name, fmt.Sprintf("Trophies awarded in track %s", track.Name), defaultTrackAwards.Trophies},
+		)
+	}
+	for _, field := range counts {
+		count, err := presetOrPrompt(field.preset, func() (int, error) {
+			return prompts.CountPrompt(p, field.key, field.message, field.defaultCount)
+		})
+		if err != nil {
+			return sciolyff_models.SciolyFF{}, err
+		}
+		*field.value = count
+	}
+
+	teams := slices.Clone(table.Schools)
+	if opts.SchoolAliases != nil {
+		if err := canonicalizeSchools(teams, opts.SchoolAliases, p, logWriter); err != nil {
+			return sciolyff_models.SciolyFF{}, err
+		}
 	}
 
 	copy_of_placings := make([]sciolyff_models.Placing, len(placings))
 	for i, p := range placings {
 		copy_of_placings[i] = *p
 	}
-	return sciolyff_models.SciolyFF{Tournament: tournament, Tracks: tracks, Events: events, Teams: table.Schools, Placings: copy_of_placings}
+	sciolyffDump := sciolyff_models.SciolyFF{Tournament: tournament, Tracks: tracks, Events: events, Teams: teams, Placings: copy_of_placings}
+	sortResults(&sciolyffDump, opts.PlacingOrder)
+	return sciolyffDump, nil
+}
+
+// Checks that every team has exactly one score per event
+func checkScoreCounts(table parsers.Table) error {
+	for _, team := range table.Schools {
+		switch {
+		case len(team.Scores) < len(table.Events):
+			return fmt.Errorf("team %d (%s) has no score in event %s", team.TeamNumber, team.Name, table.Events[len(team.Scores)].Name)
+		case len(team.Scores) > len(table.Events):
+			return fmt.Errorf("team %d (%s) has %d scores, but there are only %d events", team.TeamNumber, team.Name, len(team.Scores), len(table.Events))
+		}
+	}
+	return nil
+}
+
+// MarkTies marks every placing that shares its place in an event with another
+// placing, and unmarks the rest
+func MarkTies(s *sciolyff_models.SciolyFF) {
+	placingsByEvent := map[string][]*sciolyff_models.Placing{}
+	for i := range s.Placings {
+		p := &s.Placings[i]
+		placingsByEvent[p.Event] = append(placingsByEvent[p.Event], p)
+	}
+	markTies(slices.Collect(maps.Values(placingsByEvent)))
+}
+
+// Ranks the placings of one event within one track by their overall places
+func rankTrackPlaces(placings []*sciolyff_models.Placing) {
+	slices.SortFunc(placings, func(a, b *sciolyff_models.Placing) int {
+		if !a.EventDQ && b.EventDQ {
+			return -1
+		}
+		if !b.EventDQ && a.EventDQ {
+			return 1
+		}
+		if a.EventDQ && b.EventDQ {
+			return 0
+		}
+		if a.Participated && !b.Participated {
+			return -1
+		}
+		if b.Participated && !a.Participated {
+			return 1
+		}
+		if !a.Participated && !b.Participated {
+			return 0
+		}
+		if a.Place == 0 && b.Place != 0 {
+			return 1
+		}
+		if b.Place == 0 && a.Place != 0 {
+			return -1
+		}
+		return int(a.Place) - int(b.Place)
+	})
+
+	var previous *sciolyff_models.Placing
+	for i, p := range placings {
+		// Participation-only placings are not ranked
+		if !p.Participated || p.Place == 0 {
+			p.TrackPlace = 0
+			continue
+		}
+		// Teams tied overall are also tied within their track
+		if previous != nil && previous.Place == p.Place {
+			p.TrackPlace = previous.TrackPlace
+		} else {
+			p.TrackPlace = uint(i + 1)
+		}
+		previous = p
+	}
+}
+
+// RankTrackPlaces calculates the track places of the given events again from
+// their overall places, e.g. once the places were corrected
+func RankTrackPlaces(s *sciolyff_models.SciolyFF, events []string) {
+	trackByTeam := map[uint]string{}
+	for _, team := range s.Teams {
+		trackByTeam[team.TeamNumber] = team.Track
+	}
+	placingsByEventByTrack := map[string]map[string][]*sciolyff_models.Placing{}
+	for i := range s.Placings {
+		p := &s.Placings[i]
+		track := trackByTeam[p.TeamNumber]
+		if track == "" || !slices.Contains(events, p.Event) {
+			continue
+		}
+		if placingsByEventByTrack[p.Event] == nil {
+			placingsByEventByTrack[p.Event] = map[string][]*sciolyff_models.Placing{}
+		}
+		placingsByEventByTrack[p.Event][track] = append(placingsByEventByTrack[p.Event][track], p)
+	}
+	for _, eventPlacingsByTrack := range placingsByEventByTrack {
+		for _, placings := range eventPlacingsByTrack {
+			rankTrackPlaces(placings)
+		}
+	}
+}
+
+// Marks the placings of each event that share a place with another placing
+func markTies(placingsByEvent [][]*sciolyff_models.Placing) {
+	for _, placings := range placingsByEvent {
+		countByPlace := map[uint]int{}
+		for _, p := range placings {
+			if p.Place != 0 {
+				countByPlace[p.Place]++
+			}
+		}
+		for _, p := range placings {
+			p.Tie = p.Place != 0 && countByPlace[p.Place] > 1
+		}
+	}
+}
+
+// Adds err to unanswered if it only means a question went unanswered, so that
+// the remaining questions can still be asked. Any other error is returned.
+func collectUnanswered(err error, unanswered *[]error) error {
+	if errors.Is(err, prompts.ErrNoAnswer) {
+		*unanswered = append(*unanswered, err)
+		return nil
+	}
+	return err
+}
+
+// Returns the preset value if one was given, otherwise prompts for it
+func presetOrPrompt[T any](preset *T, prompt func() (T, error)) (T, error) {
+	if preset != nil {
+		return *preset, nil
+	}
+	return prompt()
+}
+
+// A count that is either preset or prompted for with a default
+type countField struct {
+	value        *int
+	preset       *int
+	key          string
+	message      string
+	defaultCount int
 }
@@ -0,0 +1,67 @@
+package sciolyff_test
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/Nydauron/avocado2sciolyff/parsers"
+	"github.com/Nydauron/avocado2sciolyff/prompts"
+	"github.com/Nydauron/avocado2sciolyff/sciolyff"
+	sciolyff_models "github.com/Nydauron/avocado2sciolyff/sciolyff/models"
+)
+
+// Three teams in two events and a trial event left out of the totals
+var invitational = parsers.Table{
+	Events: []parsers.AvogadroEvent{{Name: "Codebusters"}, {Name: "Optics"}, {Name: "Wind Power", IsMarkedAsTrial: true}},
+	Schools: []sciolyff_models.School{
+		{TeamNumber: 1, Name: "Troy HS", Scores: []uint{1, 2, 1}, TotalScore: "3"},
+		{TeamNumber: 2, Name: "Lincoln HS", Scores: []uint{2, 1, 3}, TotalScore: "3"},
+		{TeamNumber: 3, Name: "Oak HS", Scores: []uint{3, 4, 2}, TotalScore: "7"},
+	},
+}
+
+func TestGenerateSciolyFFFromScriptedAnswers(t *testing.T) {
+	answers := strings.Join([]string{
+		"Naperville Invitational", "", "Naperville North HS", "i", "IL", "c", "2/3/24", "", "",
+		// Wind Power was a trial event, and track places are not calculated
+		"1", "n",
+		"3", "", "",
+	}, "\n")
+	s, err := sciolyff.GenerateSciolyFF(invitational, nil, prompts.NewScriptedPrompter(strings.NewReader(answers)), sciolyff.Options{Log: io.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := sciolyff_models.TournamentMetadata{
+		Name: "Naperville Invitational", Location: "Naperville North HS", Level: "Invitational", State: "IL",
+		Division: "C", Year: 2024, Date: "2024-02-03", Medals: 3, Trophies: 3,
+	}
+	if s.Tournament != want {
+		t.Errorf("got tournament %+v, want %+v", s.Tournament, want)
+	}
+	if !s.Events[2].IsTrial {
+		t.Errorf("Wind Power was not made a trial event")
+	}
+
+	_, err = sciolyff.GenerateSciolyFF(invitational, nil, prompts.NewScriptedPrompter(strings.NewReader("Naperville Invitational\n\nNaperville North HS\nx\n")), sciolyff.Options{Log: io.Discard})
+	if err == nil || !strings.Contains(err.Error(), "scripted answer for level is invalid") {
+		t.Errorf("got %v for an invalid scripted level", err)
+	}
+}
+
+func TestGenerateSciolyFFListsEveryUnansweredQuestion(t *testing.T) {
+	opts := sciolyff.Options{
+		Inferred: parsers.InferredMetadata{Name: "Naperville Invitational", Level: "Invitational", State: "IL", Division: "C"},
+		Log:      io.Discard,
+	}
+	_, err := sciolyff.GenerateSciolyFF(invitational, nil, prompts.DefaultsPrompter{}, opts)
+	if !errors.Is(err, prompts.ErrNoAnswer) {
+		t.Fatalf("got %v, want unanswered questions", err)
+	}
+	for _, key := range []string{"location", "date", "year", "trial:Wind Power"} {
+		if !strings.Contains(err.Error(), "no answer available for "+key+":") {
+			t.Errorf("%s is not listed in %v", key, err)
+		}
+	}
+}
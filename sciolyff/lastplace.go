@@ -0,0 +1,107 @@
+package sciolyff
+
+import (
+	"fmt"
+
+	sciolyff_models "github.com/Nydauron/avocado2sciolyff/sciolyff/models"
+)
+
+// A team's score in the groups table for an event, along with the N of that
+// event within the team's track
+type trackScore struct {
+	score uint
+	n     uint
+}
+
+// Decides for every team scoring exactly N in an event whether it got last
+// place or was only awarded participation points, as both score the same.
+// Placings found to be last place are given an explicit place; the rest are
+// left as participation-only. Returns a description of every placing that could
+// not be settled either way.
+//
+// placings and scores hold the placing and raw score of every team in the
+// event, in the same order. trackScores holds the groups table score of each
+// team, if a groups table was provided.
+//
+// The reported totals are not used: last place and participation points add
+// the same N to a team's total, so every total reconciles either way.
+func resolveLastPlaces(n uint, scoring ScoringModel, placings []*sciolyff_models.Placing, scores []uint, trackScores map[uint]trackScore) []string {
+	placedCountByPlace := map[uint]uint{}
+	tiedAtNCount := 0
+	for _, score := range scores {
+		switch DecodeScore(score, n) {
+		case ScorePlace:
+			placedCountByPlace[score]++
+		case ScoreParticipationOnly:
+			tiedAtNCount++
+		}
+	}
+	// With places given as 1, 2, 2, 4, ..., the place after the highest one is
+	// that place plus the number of teams sharing it
+	nextPlace := uint(1)
+	for place, count := range placedCountByPlace {
+		nextPlace = max(nextPlace, place+count)
+	}
+	// Every place before it has to have been given out for the distinct places
+	// to account for every placed team
+	placedCount := uint(0)
+	for _, count := range placedCountByPlace {
+		placedCount += count
+	}
+	arePlacesComplete := placedCount == nextPlace-1
+
+	ambiguous := []string{}
+	for i, p := range placings {
+		if DecodeScore(scores[i], n) != ScoreParticipationOnly {
+			continue
+		}
+		// Last place is N only if every other place up to N was given out.
+		// Otherwise the score can only be participation points.
+		if !arePlacesComplete || nextPlace != n {
+			continue
+		}
+		// Per-event N based on the highest place means someone scoring N got
+		// that place. Without an offset, a lone team scoring N must be it.
+		if scoring.PerEventN == PerEventNPlace && scoring.NOffset == 0 && tiedAtNCount == 1 {
+			p.Place = n
+			continue
+		}
+		// Participation points within the track also score the track's N, so a
+		// real place in the groups table means a real place overall
+		if ts, ok := trackScores[p.TeamNumber]; ok && DecodeScore(ts.score, ts.n) == ScorePlace {
+			p.Place = n
+			continue
+		}
+		ambiguous = append(ambiguous, fmt.Sprintf("team %d scored %d in %s, which is either last place or participation points", p.TeamNumber, n, p.Event))
+	}
+	return ambiguous
+}
+
+// Collects the groups table score of every team in an event along with the N
+// of the event within each team's track. Tracks whose scores do not fit the
+// scoring model are left out.
+func eventTrackScores(event string, teams []sciolyff_models.School, teamCountPerTrack map[string]uint, groupScoresByTeam map[uint]map[string]uint, scoring ScoringModel) map[uint]trackScore {
+	scoresByTrack := map[string][]uint{}
+	for _, team := range teams {
+		if score, ok := groupScoresByTeam[team.TeamNumber][event]; ok {
+			scoresByTrack[team.Track] = append(scoresByTrack[team.Track], score)
+		}
+	}
+
+	trackNs := map[string]uint{}
+	for track, scores := range scoresByTrack {
+		if n, ok := scoring.EventN(teamCountPerTrack[track], scores); ok {
+			trackNs[track] = n
+		}
+	}
+
+	trackScores := map[uint]trackScore{}
+	for _, team := range teams {
+		score, hasScore := groupScoresByTeam[team.TeamNumber][event]
+		n, hasN := trackNs[team.Track]
+		if hasScore && hasN {
+			trackScores[team.TeamNumber] = trackScore{score: score, n: n}
+		}
+	}
+	return trackScores
+}
@@ -1,52 +1,137 @@
 package sciolyff_models
 
+import (
+	"encoding/json"
+
+	"gopkg.in/yaml.v3"
+)
+
 type SciolyFF struct {
-	Tournament TournamentMetadata `yaml:"Tournament"`
-	Tracks     []Track            `yaml:"Tracks,omitempty"`
-	Events     []Event            `yaml:"Events"`
-	Teams      []School           `yaml:"Teams"`
-	Placings   []Placing          `yaml:"Placings"`
+	Tournament TournamentMetadata `yaml:"Tournament" json:"Tournament"`
+	Tracks     []Track            `yaml:"Tracks,omitempty" json:"Tracks,omitempty"`
+	Events     []Event            `yaml:"Events" json:"Events"`
+	Teams      []School           `yaml:"Teams" json:"Teams"`
+	Placings   []Placing          `yaml:"Placings" json:"Placings"`
 }
 
 type Track struct {
-	Name string `yaml:"name"`
+	Name     string `yaml:"name" json:"name"`
+	Medals   int    `yaml:"medals,omitempty" json:"medals,omitempty"`
+	Trophies int    `yaml:"trophies,omitempty" json:"trophies,omitempty"`
 }
 
 type TournamentMetadata struct {
-	Name      string `yaml:"name"`
-	ShortName string `yaml:"short name,omitempty"`
-	Location  string `yaml:"location"`
-	Level     string `yaml:"level"`
-	State     string `yaml:"state"`
-	Division  string `yaml:"division"`
-	Year      int    `yaml:"year"`
-	Date      string `yaml:"date"`
+	Name                 string `yaml:"name" json:"name"`
+	ShortName            string `yaml:"short name,omitempty" json:"short name,omitempty"`
+	Location             string `yaml:"location" json:"location"`
+	Level                string `yaml:"level" json:"level"`
+	State                string `yaml:"state" json:"state"`
+	Division             string `yaml:"division" json:"division"`
+	Year                 int    `yaml:"year" json:"year"`
+	Date                 string `yaml:"date" json:"date"`
+	StartDate            string `yaml:"start date,omitempty" json:"start date,omitempty"`
+	EndDate              string `yaml:"end date,omitempty" json:"end date,omitempty"`
+	AwardsDate           string `yaml:"awards date,omitempty" json:"awards date,omitempty"`
+	TestDate             string `yaml:"test date,omitempty" json:"test date,omitempty"`
+	Medals               int    `yaml:"medals,omitempty" json:"medals,omitempty"`
+	Trophies             int    `yaml:"trophies,omitempty" json:"trophies,omitempty"`
+	Bids                 int    `yaml:"bids,omitempty" json:"bids,omitempty"`
+	BidsPerSchool        int    `yaml:"bids per school,omitempty" json:"bids per school,omitempty"`
+	WorstPlacingsDropped int    `yaml:"worst placings dropped,omitempty" json:"worst placings dropped,omitempty"`
+	NOffset              int    `yaml:"n offset,omitempty" json:"n offset,omitempty"`
+	PerEventN            string `yaml:"per-event n,omitempty" json:"per-event n,omitempty"`
 }
 
 type Event struct {
-	Name               string `yaml:"name"`
-	IsTrial            bool   `yaml:"trial"`
-	TrialedNormalEvent bool   `yaml:"trialed"`
-	ScoringObjective   string `yaml:"scoring,omitempty"`
+	Name               string `yaml:"name" json:"name"`
+	IsTrial            bool   `yaml:"trial" json:"trial"`
+	TrialedNormalEvent bool   `yaml:"trialed" json:"trialed"`
+	ScoringObjective   string `yaml:"scoring,omitempty" json:"scoring,omitempty"`
 }
 
 type Placing struct {
-	Event        string `yaml:"event"`
-	TeamNumber   uint   `yaml:"team"`
-	Participated bool   `yaml:"participated"`
-	EventDQ      bool   `yaml:"disqualified"`
-	Exempt       bool   `yaml:"exempt"`
-	Unknown      bool   `yaml:"unknown"`
-	Tie          bool   `yaml:"tie"`
-	Place        uint   `yaml:"place,omitempty"`
-	TrackPlace   uint   `yaml:"track place,omitempty"`
+	Event        string `yaml:"event" json:"event"`
+	TeamNumber   uint   `yaml:"team" json:"team"`
+	Participated bool   `yaml:"participated" json:"participated"`
+	EventDQ      bool   `yaml:"disqualified" json:"disqualified"`
+	Exempt       bool   `yaml:"exempt" json:"exempt"`
+	Unknown      bool   `yaml:"unknown" json:"unknown"`
+	Tie          bool   `yaml:"tie" json:"tie"`
+	Place        uint   `yaml:"place,omitempty" json:"place,omitempty"`
+	TrackPlace   uint   `yaml:"track place,omitempty" json:"track place,omitempty"`
 }
 
 type School struct {
-	TeamNumber uint   `yaml:"number"`
-	Name       string `yaml:"school"`
-	Track      string `yaml:"track"`
-	Scores     []uint `yaml:"-"`
-	TotalScore string `yaml:"-"`
-	Rank       string `yaml:"-"`
+	TeamNumber uint   `yaml:"number" json:"number"`
+	Name       string `yaml:"school" json:"school"`
+	Track      string `yaml:"track,omitempty" json:"track,omitempty"`
+	Scores     []uint `yaml:"-" json:"-"`
+	TotalScore string `yaml:"-" json:"-"`
+	Rank       string `yaml:"-" json:"-"`
+
+	// Whether the file the team was read from gave it an empty track, which
+	// sciolyff does not allow
+	hasEmptyTrack bool
+}
+
+// HasEmptyTrack reports whether the team was read with a track that is given
+// but empty
+func (s School) HasEmptyTrack() bool {
+	return s.hasEmptyTrack
+}
+
+// Only used to tell a missing track apart from an empty one
+type schoolTrack struct {
+	Track *string `yaml:"track" json:"track"`
+}
+
+func (s *School) UnmarshalYAML(node *yaml.Node) error {
+	type plainSchool School
+	school := plainSchool{}
+	track := schoolTrack{}
+	if err := node.Decode(&school); err != nil {
+		return err
+	}
+	if err := node.Decode(&track); err != nil {
+		return err
+	}
+	*s = School(school)
+	s.hasEmptyTrack = track.Track != nil && *track.Track == ""
+	return nil
+}
+
+func (s *School) UnmarshalJSON(data []byte) error {
+	type plainSchool School
+	school := plainSchool{}
+	track := schoolTrack{}
+	if err := json.Unmarshal(data, &school); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, &track); err != nil {
+		return err
+	}
+	*s = School(school)
+	s.hasEmptyTrack = track.Track != nil && *track.Track == ""
+	return nil
+}
+
+// sciolyff assumes a team participated unless told otherwise
+func (p *Placing) UnmarshalYAML(node *yaml.Node) error {
+	type plainPlacing Placing
+	placing := plainPlacing{Participated: true}
+	if err := node.Decode(&placing); err != nil {
+		return err
+	}
+	*p = Placing(placing)
+	return nil
+}
+
+func (p *Placing) UnmarshalJSON(data []byte) error {
+	type plainPlacing Placing
+	placing := plainPlacing{Participated: true}
+	if err := json.Unmarshal(data, &placing); err != nil {
+		return err
+	}
+	*p = Placing(placing)
+	return nil
 }
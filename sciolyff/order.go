@@ -0,0 +1,44 @@
+package sciolyff
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	sciolyff_models "github.com/Nydauron/avocado2sciolyff/sciolyff/models"
+)
+
+// Orders the placings can be listed in
+const (
+	// By event name, then team number
+	PlacingOrderEvent = "event"
+	// By team number, then event name
+	PlacingOrderTeam = "team"
+)
+
+// Accepts a placing order, defaulting to ordering by event when empty
+func ParsePlacingOrder(input string) (string, error) {
+	switch order := strings.ToLower(strings.TrimSpace(input)); order {
+	case "", PlacingOrderEvent:
+		return PlacingOrderEvent, nil
+	case PlacingOrderTeam:
+		return PlacingOrderTeam, nil
+	}
+	return "", fmt.Errorf("%q is not a placing order (%s or %s)", input, PlacingOrderEvent, PlacingOrderTeam)
+}
+
+// Puts the results in their canonical order so that the same input always
+// gives the same output: tracks and events by name, teams by number and
+// placings in the given order
+func sortResults(s *sciolyff_models.SciolyFF, placingOrder string) {
+	slices.SortFunc(s.Tracks, func(a, b sciolyff_models.Track) int { return cmp.Compare(a.Name, b.Name) })
+	slices.SortFunc(s.Events, func(a, b sciolyff_models.Event) int { return cmp.Compare(a.Name, b.Name) })
+	slices.SortFunc(s.Teams, func(a, b sciolyff_models.School) int { return cmp.Compare(a.TeamNumber, b.TeamNumber) })
+	slices.SortFunc(s.Placings, func(a, b sciolyff_models.Placing) int {
+		if placingOrder == PlacingOrderTeam {
+			return cmp.Or(cmp.Compare(a.TeamNumber, b.TeamNumber), cmp.Compare(a.Event, b.Event))
+		}
+		return cmp.Or(cmp.Compare(a.Event, b.Event), cmp.Compare(a.TeamNumber, b.TeamNumber))
+	})
+}
@@ -0,0 +1,60 @@
+package sciolyff_test
+
+import (
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/Nydauron/avocado2sciolyff/parsers"
+	"github.com/Nydauron/avocado2sciolyff/prompts"
+	"github.com/Nydauron/avocado2sciolyff/sciolyff"
+	sciolyff_models "github.com/Nydauron/avocado2sciolyff/sciolyff/models"
+)
+
+func TestParsePlacingOrder(t *testing.T) {
+	tests := map[string]string{"": sciolyff.PlacingOrderEvent, "Event": sciolyff.PlacingOrderEvent, " team ": sciolyff.PlacingOrderTeam}
+	for input, want := range tests {
+		if got, err := sciolyff.ParsePlacingOrder(input); got != want || err != nil {
+			t.Errorf("ParsePlacingOrder(%q) = %q, %v, want %q", input, got, err, want)
+		}
+	}
+	if _, err := sciolyff.ParsePlacingOrder("place"); err == nil {
+		t.Error("an unknown placing order was accepted")
+	}
+}
+
+func TestGenerateSciolyFFOrdersResults(t *testing.T) {
+	// Neither the events nor the teams are in order in the input
+	unordered := parsers.Table{
+		Events: []parsers.AvogadroEvent{{Name: "Optics"}, {Name: "Codebusters"}},
+		Schools: []sciolyff_models.School{
+			{TeamNumber: 2, Name: "Lincoln HS", Scores: []uint{1, 2}, TotalScore: "3"},
+			{TeamNumber: 1, Name: "Troy HS", Scores: []uint{2, 1}, TotalScore: "3"},
+		},
+	}
+	location, date, year := "Naperville North HS", "2024-02-03", 2024
+	tests := map[string][]string{
+		sciolyff.PlacingOrderEvent: {"Codebusters 1", "Codebusters 2", "Optics 1", "Optics 2"},
+		sciolyff.PlacingOrderTeam:  {"Codebusters 1", "Optics 1", "Codebusters 2", "Optics 2"},
+	}
+	for order, want := range tests {
+		opts := sciolyff.Options{
+			Location: &location, Date: &date, Year: &year,
+			Inferred:     parsers.InferredMetadata{Name: "Naperville Invitational", Level: "Invitational", State: "IL", Division: "C"},
+			PlacingOrder: order,
+			Log:          io.Discard,
+		}
+		s, err := sciolyff.GenerateSciolyFF(unordered, nil, prompts.DefaultsPrompter{}, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s.Events[0].Name != "Codebusters" || s.Teams[0].TeamNumber != 1 {
+			t.Errorf("got events %+v and teams %+v", s.Events, s.Teams)
+		}
+		for i, placing := range s.Placings {
+			if got := fmt.Sprintf("%s %d", placing.Event, placing.TeamNumber); got != want[i] {
+				t.Errorf("ordering by %s, placing %d is %s, want %s", order, i, got, want[i])
+			}
+		}
+	}
+}
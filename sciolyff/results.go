@@ -0,0 +1,135 @@
+package sciolyff
+
+import (
+	"cmp"
+	"slices"
+
+	sciolyff_models "github.com/Nydauron/avocado2sciolyff/sciolyff/models"
+)
+
+// TeamResult holds a team's points in every event along with its total and
+// overall rank, as sciolyff scores them
+type TeamResult struct {
+	Team sciolyff_models.School
+	// Points by event name. Exempt and unknown placings are left out.
+	Points map[string]uint
+	// Events whose points were dropped as one of the team's worst placings
+	Dropped map[string]bool
+	Total   uint
+	// Rank by total, with ties broken by the most first places, then second
+	// places and so on. Teams that are still tied share a rank.
+	Rank uint
+}
+
+// EventNs returns the N of each event under the tournament's scoring model,
+// by event name
+func EventNs(s sciolyff_models.SciolyFF) map[string]uint {
+	highestPlaces := map[string]uint{}
+	participantCounts := map[string]uint{}
+	for _, p := range s.Placings {
+		highestPlaces[p.Event] = max(highestPlaces[p.Event], p.Place)
+		if p.Participated {
+			participantCounts[p.Event]++
+		}
+	}
+	eventNs := map[string]uint{}
+	for _, event := range s.Events {
+		n := uint(len(s.Teams))
+		switch s.Tournament.PerEventN {
+		case PerEventNPlace:
+			n = highestPlaces[event.Name]
+		case PerEventNParticipation:
+			n = participantCounts[event.Name]
+		}
+		eventNs[event.Name] = n + uint(s.Tournament.NOffset)
+	}
+	return eventNs
+}
+
+// PlacingPoints returns the points a placing scores in an event with the given
+// N. Exempt and unknown placings score nothing and return false.
+func PlacingPoints(p sciolyff_models.Placing, n uint) (uint, bool) {
+	switch {
+	case p.Exempt || p.Unknown:
+		return 0, false
+	case p.EventDQ:
+		return n + 2, true
+	case !p.Participated:
+		return n + 1, true
+	case p.Place == 0:
+		return n, true
+	default:
+		return p.Place, true
+	}
+}
+
+// ScoreResults totals every team's points and ranks the teams, best first.
+// Trial and trialed events do not count towards totals, and the tournament's
+// worst placings are dropped.
+func ScoreResults(s sciolyff_models.SciolyFF) []TeamResult {
+	eventNs := EventNs(s)
+	countedEvents := map[string]bool{}
+	for _, event := range s.Events {
+		countedEvents[event.Name] = !event.IsTrial && !event.TrialedNormalEvent
+	}
+
+	results := make([]TeamResult, 0, len(s.Teams))
+	resultIdxByTeam := map[uint]int{}
+	for _, team := range s.Teams {
+		resultIdxByTeam[team.TeamNumber] = len(results)
+		results = append(results, TeamResult{Team: team, Points: map[string]uint{}, Dropped: map[string]bool{}})
+	}
+	for _, p := range s.Placings {
+		i, ok := resultIdxByTeam[p.TeamNumber]
+		if !ok {
+			continue
+		}
+		if points, ok := PlacingPoints(p, eventNs[p.Event]); ok {
+			results[i].Points[p.Event] = points
+		}
+	}
+
+	// Counted points of each team, best first, for breaking ties
+	countedPoints := make([][]uint, len(results))
+	for i := range results {
+		result := &results[i]
+		events := []string{}
+		for event := range result.Points {
+			if countedEvents[event] {
+				events = append(events, event)
+			}
+		}
+		// Worst first, with event names keeping the order stable
+		slices.SortFunc(events, func(a, b string) int {
+			return cmp.Or(cmp.Compare(result.Points[b], result.Points[a]), cmp.Compare(a, b))
+		})
+		for j, event := range events {
+			if j < s.Tournament.WorstPlacingsDropped {
+				result.Dropped[event] = true
+				continue
+			}
+			result.Total += result.Points[event]
+			countedPoints[i] = append(countedPoints[i], result.Points[event])
+		}
+		slices.Sort(countedPoints[i])
+	}
+
+	order := make([]int, len(results))
+	for i := range order {
+		order[i] = i
+	}
+	compareResults := func(a, b int) int {
+		return cmp.Or(cmp.Compare(results[a].Total, results[b].Total), slices.Compare(countedPoints[a], countedPoints[b]))
+	}
+	slices.SortStableFunc(order, compareResults)
+	ranked := make([]TeamResult, len(results))
+	for rank, i := range order {
+		ranked[rank] = results[i]
+		if rank > 0 && compareResults(order[rank-1], i) == 0 {
+			ranked[rank].Rank = ranked[rank-1].Rank
+		} else {
+			ranked[rank].Rank = uint(rank + 1)
+		}
+	}
+	return ranked
+}
@@ -0,0 +1,93 @@
+package sciolyff
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/Nydauron/avocado2sciolyff/prompts"
+	"github.com/Nydauron/avocado2sciolyff/schools"
+	sciolyff_models "github.com/Nydauron/avocado2sciolyff/sciolyff/models"
+)
+
+// Matches a trailing marker that tells apart the teams of a school, e.g. a
+// single letter as in "Troy High School A", "Blue", "JV", "Team 2" or "#2",
+// optionally in parentheses. Other parentheticals are kept, as they often tell apart
+// schools of the same name, e.g. "Lincoln HS (Portland)".
+var teamSuffixRegex = regexp.MustCompile(`^(.+?)([\s-]+\(?(?:[A-Z]|(?i:blue|gold|varsity|jv|team\s*#?\s*\d+)|#\s*\d+)\)?)$`)
+
+// Splits a team's name into the name of its school and the team suffix, if
+// any, along with the separator before it (e.g. " A")
+func splitTeamName(name string) (string, string) {
+	name = strings.TrimSpace(name)
+	if match := teamSuffixRegex.FindStringSubmatch(name); match != nil {
+		return match[1], match[2]
+	}
+	return name, ""
+}
+
+// Strips the team suffix from a team's name, leaving the name of its school
+func schoolOfTeam(name string) string {
+	school, _ := splitTeamName(name)
+	return school
+}
+
+// Rewrites the school name of each team to its canonical name from the alias
+// file, keeping the team suffix. Names with no alias that look like a known
+// school are offered as a new alias, and unrecognized names are offered as a
+// new school. Renamed schools are noted in logWriter. The teams are updated in
+// place, so callers pass their own copy.
+func canonicalizeSchools(teams []sciolyff_models.School, aliases *schools.Aliases, p prompts.Prompter, logWriter io.Writer) error {
+	// Canonical names by the name each school appears under, so that every
+	// team of a school is only asked about once
+	canonicalNames := map[string]string{}
+	for i, team := range teams {
+		school, suffix := splitTeamName(team.Name)
+		canonicalName, ok := canonicalNames[school]
+		if !ok {
+			var err error
+			if canonicalName, err = canonicalSchoolName(school, aliases, p); err != nil {
+				return err
+			}
+			canonicalNames[school] = canonicalName
+			if canonicalName != school {
+				fmt.Fprintf(logWriter, "Renaming school %s to %s\n", school, canonicalName)
+			}
+		}
+		teams[i].Name = canonicalName + suffix
+	}
+	return nil
+}
+
+func canonicalSchoolName(name string, aliases *schools.Aliases, p prompts.Prompter) (string, error) {
+	if canonicalName, ok := aliases.Canonical(name); ok {
+		return canonicalName, nil
+	}
+
+	if suggestion, ok := aliases.Suggest(name); ok {
+		isSameSchool, err := prompts.ConfirmPrompt(p, "school:"+name, fmt.Sprintf("School %s is not in the alias file. Is it %s?", name, suggestion), false)
+		if err != nil {
+			return "", err
+		}
+		if isSameSchool {
+			isRecorded, err := prompts.ConfirmPrompt(p, "record alias:"+name, fmt.Sprintf("Record %s as an alias of %s?", name, suggestion), true)
+			if err != nil {
+				return "", err
+			}
+			if isRecorded {
+				aliases.Add(suggestion, name)
+			}
+			return suggestion, nil
+		}
+	}
+
+	isRecorded, err := prompts.ConfirmPrompt(p, "record school:"+name, fmt.Sprintf("Record %s as a new school in the alias file?", name), false)
+	if err != nil {
+		return "", err
+	}
+	if isRecorded {
+		aliases.Add(name, name)
+	}
+	return name, nil
+}
@@ -0,0 +1,56 @@
+package sciolyff
+
+import (
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Nydauron/avocado2sciolyff/prompts"
+	"github.com/Nydauron/avocado2sciolyff/schools"
+	sciolyff_models "github.com/Nydauron/avocado2sciolyff/sciolyff/models"
+)
+
+func TestSplitTeamName(t *testing.T) {
+	tests := []struct{ name, school, suffix string }{
+		{"Troy HS A", "Troy HS", " A"},
+		{"Troy HS (B)", "Troy HS", " (B)"},
+		{"Troy HS C", "Troy HS", " C"},
+		// Only capital letters are markers
+		{"Troy HS c", "Troy HS c", ""},
+		{"Oak Team 2", "Oak", " Team 2"},
+		{"Maple - JV", "Maple", " - JV"},
+		{"Lincoln HS (Portland)", "Lincoln HS (Portland)", ""},
+		{" Novi HS ", "Novi HS", ""},
+	}
+	for _, tt := range tests {
+		if school, suffix := splitTeamName(tt.name); school != tt.school || suffix != tt.suffix {
+			t.Errorf("splitTeamName(%q) = %q, %q, want %q, %q", tt.name, school, suffix, tt.school, tt.suffix)
+		}
+	}
+}
+
+func TestCanonicalizeSchoolsKeepsTeamSuffixes(t *testing.T) {
+	aliases, err := schools.Load(filepath.Join(t.TempDir(), "schools.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	aliases.Add("Troy High School", "Troy High School")
+	teams := []sciolyff_models.School{{Name: "Troy HS A"}, {Name: "Troy HS B"}, {Name: "Troy Hgh School"}, {Name: "Novi HS"}}
+
+	// "Troy Hgh School" is confirmed as an alias without recording it, and
+	// Novi is not recorded as a new school
+	p := prompts.NewScriptedPrompter(strings.NewReader("y\nn\nn\n"))
+	if err := canonicalizeSchools(teams, aliases, p, io.Discard); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"Troy High School A", "Troy High School B", "Troy High School", "Novi HS"}
+	for i, team := range teams {
+		if team.Name != want[i] {
+			t.Errorf("team %d: got %q, want %q", i, team.Name, want[i])
+		}
+	}
+	if _, err := p.Ask(prompts.Question{Key: "extra"}); err == nil {
+		t.Error("a school was asked about more than once")
+	}
+}
@@ -0,0 +1,267 @@
+package sciolyff
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Nydauron/avocado2sciolyff/parsers"
+)
+
+// DetectWorstPlacingsDropped finds how many worst placings have to be dropped
+// from each team's scores to reproduce the totals Avogadro reports. Events
+// marked as trial never count towards a total and are left out of the sums.
+// Returns false if the totals are missing or no number of dropped placings
+// matches every team.
+func DetectWorstPlacingsDropped(table parsers.Table) (int, bool) {
+	if len(table.Schools) == 0 {
+		return 0, false
+	}
+
+	countedScoresByTeam := make([][]uint, 0, len(table.Schools))
+	totals := make([]uint, 0, len(table.Schools))
+	for _, team := range table.Schools {
+		total, err := strconv.ParseUint(strings.TrimSpace(team.TotalScore), 10, 32)
+		if err != nil || len(team.Scores) != len(table.Events) {
+			return 0, false
+		}
+		countedScores := []uint{}
+		for i, score := range team.Scores {
+			if !table.Events[i].IsMarkedAsTrial {
+				countedScores = append(countedScores, score)
+			}
+		}
+		// Worst (highest) scores go last so dropping k placings trims the tail
+		slices.Sort(countedScores)
+		countedScoresByTeam = append(countedScoresByTeam, countedScores)
+		totals = append(totals, uint(total))
+	}
+
+	countedEvents := len(countedScoresByTeam[0])
+	for dropped := 0; dropped < countedEvents; dropped++ {
+		matchesAllTeams := true
+		for i, countedScores := range countedScoresByTeam {
+			if sumScores(countedScores[:len(countedScores)-dropped]) != totals[i] {
+				matchesAllTeams = false
+				break
+			}
+		}
+		if matchesAllTeams {
+			return dropped, true
+		}
+	}
+	return 0, false
+}
+
+func sumScores(scores []uint) uint {
+	var sum uint
+	for _, score := range scores {
+		sum += score
+	}
+	return sum
+}
+
+const (
+	PerEventNNone          = ""
+	PerEventNPlace         = "place"
+	PerEventNParticipation = "participation"
+)
+
+// Kinds of results a raw Avogadro score can stand for
+const (
+	ScorePlace = iota
+	ScoreParticipationOnly
+	ScoreNoShow
+	ScoreDisqualified
+	ScoreInvalid
+)
+
+// ScoringModel describes how sciolyff turns placings into points. Every event
+// has a value N: participation-only placings score N, no-shows N+1 and
+// disqualifications N+2. N is the number of teams by default, or is computed
+// per event from the highest place given out ("place") or the number of teams
+// that participated ("participation"). NOffset is added to N in every case.
+type ScoringModel struct {
+	PerEventN string
+	NOffset   int
+}
+
+// Scoring models tried by DetectScoringModel, in order of preference
+var detectableScoringModels = func() []ScoringModel {
+	models := []ScoringModel{}
+	for _, perEventN := range []string{PerEventNNone, PerEventNParticipation, PerEventNPlace} {
+		for offset := 0; offset <= maxDetectableNOffset; offset++ {
+			models = append(models, ScoringModel{PerEventN: perEventN, NOffset: offset})
+		}
+	}
+	return models
+}()
+
+const maxDetectableNOffset = 3
+
+// Accepts a sciolyff per-event n value, or "none" for N being the team count
+func ParsePerEventN(input string) (string, error) {
+	switch input {
+	case PerEventNNone, "none":
+		return PerEventNNone, nil
+	case PerEventNPlace, PerEventNParticipation:
+		return input, nil
+	}
+	return "", fmt.Errorf("%q is not \"none\", \"place\" or \"participation\"", input)
+}
+
+// DecodeScore returns what kind of result a score is for an event with the
+// given N.
+func DecodeScore(score uint, n uint) int {
+	switch {
+	case score < n:
+		return ScorePlace
+	case score == n:
+		return ScoreParticipationOnly
+	case score == n+1:
+		return ScoreNoShow
+	case score == n+2:
+		return ScoreDisqualified
+	default:
+		return ScoreInvalid
+	}
+}
+
+// EventN finds the N of an event under the scoring model from the raw scores
+// of every team in that event. When the scores allow more than one N, the one
+// that decodes into the fewest tied places is picked. Returns false if no N is
+// consistent with the scores.
+func (m ScoringModel) EventN(teamCount uint, scores []uint) (uint, bool) {
+	offset := uint(m.NOffset)
+	candidates := []uint{}
+	switch m.PerEventN {
+	case PerEventNNone:
+		candidates = append(candidates, teamCount+offset)
+	case PerEventNParticipation:
+		// N has to equal the number of teams scoring at most N, plus the offset
+		for n := offset; n <= teamCount+offset; n++ {
+			participated := uint(0)
+			for _, score := range scores {
+				if score <= n {
+					participated++
+				}
+			}
+			if participated+offset == n {
+				candidates = append(candidates, n)
+			}
+		}
+	case PerEventNPlace:
+		// N has to equal the highest place given out, plus the offset
+		for _, score := range scores {
+			candidates = append(candidates, score+offset)
+		}
+		slices.Sort(candidates)
+		candidates = slices.Compact(candidates)
+	}
+
+	bestN := uint(0)
+	bestTies := -1
+	for _, n := range candidates {
+		// Without an offset, the team in last place scores N just like teams
+		// that only participated, so the highest place cannot be checked
+		if m.PerEventN == PerEventNPlace && offset > 0 && highestPlace(scores, n) != n-offset {
+			continue
+		}
+		ties, ok := countTies(scores, n)
+		if !ok {
+			continue
+		}
+		// Larger candidates come later, so ties favor the N with fewer no-shows
+		if bestTies == -1 || ties <= bestTies {
+			bestN = n
+			bestTies = ties
+		}
+	}
+	return bestN, bestTies != -1
+}
+
+// Returns the highest score below limit
+func highestPlace(scores []uint, limit uint) uint {
+	highest := uint(0)
+	for _, score := range scores {
+		if score < limit {
+			highest = max(highest, score)
+		}
+	}
+	return highest
+}
+
+// Counts the number of placings that share their place with another team.
+// Returns false if a score cannot be decoded with the given N or the places
+// do not follow standard competition ranking (1, 2, 2, 4, ...).
+func countTies(scores []uint, n uint) (int, bool) {
+	places := []uint{}
+	for _, score := range scores {
+		switch DecodeScore(score, n) {
+		case ScoreInvalid:
+			return 0, false
+		case ScorePlace:
+			places = append(places, score)
+		}
+	}
+	slices.Sort(places)
+
+	ties := 0
+	expectedPlace := uint(1)
+	for i := 0; i < len(places); {
+		if places[i] != expectedPlace {
+			return 0, false
+		}
+		j := i
+		for j < len(places) && places[j] == places[i] {
+			j++
+		}
+		if j-i > 1 {
+			ties += j - i
+		}
+		expectedPlace += uint(j - i)
+		i = j
+	}
+	return ties, true
+}
+
+// EventScores returns the raw scores of every team in the event at eventIdx
+func EventScores(table parsers.Table, eventIdx int) []uint {
+	scores := make([]uint, 0, len(table.Schools))
+	for _, team := range table.Schools {
+		if eventIdx < len(team.Scores) {
+			scores = append(scores, team.Scores[eventIdx])
+		}
+	}
+	return scores
+}
+
+// DetectScoringModel finds the scoring model that explains the scores of every
+// event with the fewest tied places, preferring the sciolyff default (N is the
+// team count, no offset) when several do equally well. Returns false if no
+// model is consistent with the scores.
+func DetectScoringModel(table parsers.Table) (ScoringModel, bool) {
+	teamCount := uint(len(table.Schools))
+	bestModel := ScoringModel{}
+	bestTies := -1
+	for _, model := range detectableScoringModels {
+		modelTies := 0
+		isConsistent := true
+		for eventIdx := range table.Events {
+			scores := EventScores(table, eventIdx)
+			n, ok := model.EventN(teamCount, scores)
+			if !ok {
+				isConsistent = false
+				break
+			}
+			ties, _ := countTies(scores, n)
+			modelTies += ties
+		}
+		if isConsistent && (bestTies == -1 || modelTies < bestTies) {
+			bestModel = model
+			bestTies = modelTies
+		}
+	}
+	return bestModel, bestTies != -1
+}
@@ -0,0 +1,138 @@
+package sciolyff_test
+
+import (
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/Nydauron/avocado2sciolyff/parsers"
+	"github.com/Nydauron/avocado2sciolyff/prompts"
+	"github.com/Nydauron/avocado2sciolyff/sciolyff"
+	sciolyff_models "github.com/Nydauron/avocado2sciolyff/sciolyff/models"
+)
+
+// Builds a table from event names (trial events end in "*") and one row per
+// team of the form "1 2 3 = 6", where the total after "=" may be left out
+func table(t *testing.T, events string, rows ...string) parsers.Table {
+	t.Helper()
+	table := parsers.Table{}
+	for _, name := range strings.Fields(events) {
+		trimmedName, isTrial := strings.CutSuffix(name, "*")
+		table.Events = append(table.Events, parsers.AvogadroEvent{Name: trimmedName, IsMarkedAsTrial: isTrial})
+	}
+	for i, row := range rows {
+		scores, total, _ := strings.Cut(row, "=")
+		team := sciolyff_models.School{TeamNumber: uint(i + 1), Name: "Team " + strconv.Itoa(i+1), TotalScore: strings.TrimSpace(total)}
+		for _, score := range strings.Fields(scores) {
+			parsedScore, err := strconv.ParseUint(score, 10, 32)
+			if err != nil {
+				t.Fatalf("invalid score %q in row %q", score, row)
+			}
+			team.Scores = append(team.Scores, uint(parsedScore))
+		}
+		table.Schools = append(table.Schools, team)
+	}
+	return table
+}
+
+func TestDetectWorstPlacingsDropped(t *testing.T) {
+	tests := []struct {
+		name   string
+		table  parsers.Table
+		want   int
+		wantOK bool
+	}{
+		{"none dropped", table(t, "A B C", "1 2 3 = 6", "2 1 1 = 4"), 0, true},
+		{"worst one dropped", table(t, "A B C", "1 2 3 = 3", "2 1 1 = 2"), 1, true},
+		{"trial events left out", table(t, "A B C*", "1 2 9 = 3", "2 1 1 = 3"), 0, true},
+		{"totals missing", table(t, "A B", "1 2", "2 1"), 0, false},
+		{"no number dropped matches", table(t, "A B", "1 2 = 7", "2 1 = 3"), 0, false},
+		{"no teams", table(t, "A B"), 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := sciolyff.DetectWorstPlacingsDropped(tt.table)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("got %d, %t, want %d, %t", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestDecodeScore(t *testing.T) {
+	// With N = 5, places run up to 4 and participation scores 5
+	want := []int{sciolyff.ScorePlace, sciolyff.ScorePlace, sciolyff.ScorePlace, sciolyff.ScorePlace,
+		sciolyff.ScoreParticipationOnly, sciolyff.ScoreNoShow, sciolyff.ScoreDisqualified, sciolyff.ScoreInvalid}
+	for i, kind := range want {
+		score := uint(i + 1)
+		if got := sciolyff.DecodeScore(score, 5); got != kind {
+			t.Errorf("DecodeScore(%d, 5) = %d, want %d", score, got, kind)
+		}
+	}
+}
+
+func TestEventN(t *testing.T) {
+	tests := []struct {
+		model  sciolyff.ScoringModel
+		scores []uint
+		want   uint
+		wantOK bool
+	}{
+		{sciolyff.ScoringModel{}, []uint{1, 2, 3, 6, 7}, 5, true},
+		{sciolyff.ScoringModel{NOffset: 2}, []uint{1, 2, 8, 9, 7}, 7, true},
+		{sciolyff.ScoringModel{}, []uint{1, 2, 3, 4, 9}, 0, false},
+		{sciolyff.ScoringModel{PerEventN: sciolyff.PerEventNParticipation}, []uint{1, 2, 4, 5, 4}, 4, true},
+		{sciolyff.ScoringModel{PerEventN: sciolyff.PerEventNPlace, NOffset: 1}, []uint{1, 2, 3, 4, 6}, 5, true},
+	}
+	for _, tt := range tests {
+		got, ok := tt.model.EventN(uint(len(tt.scores)), tt.scores)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("%+v.EventN(%v) = %d, %t, want %d, %t", tt.model, tt.scores, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestDetectScoringModel(t *testing.T) {
+	tests := []struct {
+		name   string
+		table  parsers.Table
+		want   sciolyff.ScoringModel
+		wantOK bool
+	}{
+		{"sciolyff default", table(t, "A B", "1 2", "2 1", "3 4", "5 3"), sciolyff.ScoringModel{}, true},
+		{"n offset", table(t, "A B", "1 2", "2 1", "3 7", "8 3"), sciolyff.ScoringModel{NOffset: 2}, true},
+		{"no model", table(t, "A", "1", "3", "20"), sciolyff.ScoringModel{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := sciolyff.DetectScoringModel(tt.table)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("got %+v, %t, want %+v, %t", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestParsePerEventN(t *testing.T) {
+	for input, want := range map[string]string{
+		"":              sciolyff.PerEventNNone,
+		"none":          sciolyff.PerEventNNone,
+		"place":         sciolyff.PerEventNPlace,
+		"participation": sciolyff.PerEventNParticipation,
+	} {
+		if got, err := sciolyff.ParsePerEventN(input); got != want || err != nil {
+			t.Errorf("ParsePerEventN(%q) = %q, %v, want %q", input, got, err, want)
+		}
+	}
+	if _, err := sciolyff.ParsePerEventN("teams"); err == nil {
+		t.Error(`ParsePerEventN("teams") did not fail`)
+	}
+}
+
+func TestGenerateSciolyFFRejectsMissingScores(t *testing.T) {
+	_, err := sciolyff.GenerateSciolyFF(table(t, "Codebusters Optics", "1 2", "2"), nil, prompts.DefaultsPrompter{}, sciolyff.Options{Log: io.Discard})
+	if err == nil || err.Error() != "team 2 (Team 2) has no score in event Optics" {
+		t.Errorf("got %v for a team missing a score", err)
+	}
+}
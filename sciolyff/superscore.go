@@ -0,0 +1,134 @@
+package sciolyff
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+	"strings"
+
+	sciolyff_models "github.com/Nydauron/avocado2sciolyff/sciolyff/models"
+)
+
+// Added to the name of superscored tournaments
+const SuperscoreNameSuffix = " (Superscore)"
+
+// Superscore derives superscored results, where every school is one team that
+// takes the best placing of any of its teams in each event, across tracks.
+// Each event is then placed again among the superscored teams. A school's
+// superscored team keeps the lowest number of its teams. Tracks are dropped,
+// awards are limited to the number of schools, and bids are left out as they
+// go to teams rather than schools.
+func Superscore(s sciolyff_models.SciolyFF, placingOrder string) sciolyff_models.SciolyFF {
+	superscored := sciolyff_models.SciolyFF{
+		Tournament: s.Tournament,
+		Events:     slices.Clone(s.Events),
+	}
+	t := &superscored.Tournament
+	t.Name += SuperscoreNameSuffix
+	if t.ShortName != "" {
+		t.ShortName += " Superscore"
+	}
+	t.Bids = 0
+	t.BidsPerSchool = 0
+
+	// Superscored team numbers by team number, grouping the teams of each
+	// school
+	superscoredNumbers := map[uint]uint{}
+	superscoredNumbersBySchool := map[string]uint{}
+	teams := slices.Clone(s.Teams)
+	slices.SortFunc(teams, func(a, b sciolyff_models.School) int { return cmp.Compare(a.TeamNumber, b.TeamNumber) })
+	for _, team := range teams {
+		school := schoolOfTeam(team.Name)
+		key := strings.ToLower(school)
+		number, ok := superscoredNumbersBySchool[key]
+		if !ok {
+			number = team.TeamNumber
+			superscoredNumbersBySchool[key] = number
+			superscored.Teams = append(superscored.Teams, sciolyff_models.School{TeamNumber: number, Name: school})
+		}
+		superscoredNumbers[team.TeamNumber] = number
+	}
+
+	// Best placing of each school by event. Exempt and unknown placings score
+	// nothing, so they are only taken when a school has no other placing.
+	type eventTeam struct {
+		event string
+		team  uint
+	}
+	eventNs := EventNs(s)
+	bestPlacings := map[eventTeam]sciolyff_models.Placing{}
+	isBetter := func(p sciolyff_models.Placing, best sciolyff_models.Placing) bool {
+		points, isScored := PlacingPoints(p, eventNs[p.Event])
+		bestPoints, isBestScored := PlacingPoints(best, eventNs[best.Event])
+		if isScored != isBestScored {
+			return isScored
+		}
+		return cmp.Or(cmp.Compare(points, bestPoints), cmp.Compare(p.TeamNumber, best.TeamNumber)) < 0
+	}
+	for _, p := range s.Placings {
+		number, ok := superscoredNumbers[p.TeamNumber]
+		if !ok {
+			continue
+		}
+		key := eventTeam{p.Event, number}
+		if best, ok := bestPlacings[key]; !ok || isBetter(p, best) {
+			bestPlacings[key] = p
+		}
+	}
+
+	for key, p := range bestPlacings {
+		p.TeamNumber = key.team
+		p.TrackPlace = 0
+		superscored.Placings = append(superscored.Placings, p)
+	}
+	t.Medals = min(t.Medals, len(superscored.Teams))
+	t.Trophies = min(t.Trophies, len(superscored.Teams))
+
+	placingsByEventName := map[string][]*sciolyff_models.Placing{}
+	for i := range superscored.Placings {
+		p := &superscored.Placings[i]
+		placingsByEventName[p.Event] = append(placingsByEventName[p.Event], p)
+	}
+	for _, placings := range placingsByEventName {
+		placeAgain(slices.DeleteFunc(slices.Clone(placings), func(p *sciolyff_models.Placing) bool { return p.Place == 0 }))
+	}
+	// With fewer teams, a participation-only placing can now score the same
+	// as last place, which is settled as it is for the original results
+	scoring := ScoringModel{PerEventN: t.PerEventN, NOffset: t.NOffset}
+	superscoredNs := EventNs(superscored)
+	for event, placings := range placingsByEventName {
+		n := superscoredNs[event]
+		scores := make([]uint, len(placings))
+		for i, p := range placings {
+			points, ok := PlacingPoints(*p, n)
+			if !ok {
+				// Exempt and unknown placings are neither placed nor
+				// participation-only, just like no-shows
+				points = n + 1
+			}
+			scores[i] = points
+		}
+		resolveLastPlaces(n, scoring, placings, scores, nil)
+	}
+	markTies(slices.Collect(maps.Values(placingsByEventName)))
+
+	sortResults(&superscored, placingOrder)
+	return superscored
+}
+
+// Places the given placings of an event again by their current places. Teams
+// with the same place keep sharing it.
+func placeAgain(placings []*sciolyff_models.Placing) {
+	slices.SortFunc(placings, func(a, b *sciolyff_models.Placing) int { return cmp.Compare(a.Place, b.Place) })
+	newPlaces := make([]uint, len(placings))
+	for i, p := range placings {
+		if i > 0 && p.Place == placings[i-1].Place {
+			newPlaces[i] = newPlaces[i-1]
+		} else {
+			newPlaces[i] = uint(i + 1)
+		}
+	}
+	for i, p := range placings {
+		p.Place = newPlaces[i]
+	}
+}
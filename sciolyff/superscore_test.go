@@ -0,0 +1,101 @@
+package sciolyff_test
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Nydauron/avocado2sciolyff/output"
+	"github.com/Nydauron/avocado2sciolyff/sciolyff"
+	sciolyff_models "github.com/Nydauron/avocado2sciolyff/sciolyff/models"
+	"github.com/Nydauron/avocado2sciolyff/validator"
+)
+
+// A state tournament with two tracks, where Troy fields three teams, Oak two,
+// and two different schools are both called Lincoln HS
+var illinoisStates = sciolyff_models.SciolyFF{
+	Tournament: sciolyff_models.TournamentMetadata{
+		Name: "Illinois State", Location: "UIUC", Level: "States", State: "IL", Division: "C", Year: 2024, Date: "2024-04-20",
+		Medals: 6, Trophies: 6, Bids: 2, BidsPerSchool: 1,
+	},
+	Tracks: []sciolyff_models.Track{{Name: "JV", Medals: 3, Trophies: 3}, {Name: "V", Medals: 3, Trophies: 3}},
+	Events: []sciolyff_models.Event{{Name: "Codebusters"}, {Name: "Optics"}},
+	Teams: []sciolyff_models.School{
+		{TeamNumber: 1, Name: "Troy HS A", Track: "V"},
+		{TeamNumber: 2, Name: "Troy HS B", Track: "JV"},
+		{TeamNumber: 3, Name: "Lincoln HS (Portland)", Track: "V"},
+		{TeamNumber: 4, Name: "Lincoln HS (Salem)", Track: "JV"},
+		{TeamNumber: 5, Name: "Oak Team 1", Track: "V"},
+		{TeamNumber: 6, Name: "Oak Team 2", Track: "JV"},
+		{TeamNumber: 7, Name: "Maple", Track: "JV"},
+		{TeamNumber: 8, Name: "Troy HS C", Track: "JV"},
+	},
+	Placings: []sciolyff_models.Placing{
+		{Event: "Codebusters", TeamNumber: 1, Participated: true, Place: 3, TrackPlace: 2},
+		{Event: "Codebusters", TeamNumber: 2, Participated: true, Place: 1, TrackPlace: 1},
+		{Event: "Codebusters", TeamNumber: 3, Participated: true, Place: 2, TrackPlace: 1},
+		{Event: "Codebusters", TeamNumber: 4, Participated: true, Place: 4, TrackPlace: 2},
+		{Event: "Codebusters", TeamNumber: 5, Participated: true, Place: 5, TrackPlace: 3, Tie: true},
+		{Event: "Codebusters", TeamNumber: 6, Participated: true, Place: 5, TrackPlace: 3, Tie: true},
+		{Event: "Codebusters", TeamNumber: 7, Participated: true},
+		{Event: "Codebusters", TeamNumber: 8, Participated: true, Place: 7, TrackPlace: 4},
+		{Event: "Optics", TeamNumber: 1, Participated: true, Place: 1, TrackPlace: 1},
+		{Event: "Optics", TeamNumber: 2, Participated: true, Place: 2, TrackPlace: 1},
+		{Event: "Optics", TeamNumber: 3, Participated: true, Place: 3, TrackPlace: 2},
+		{Event: "Optics", TeamNumber: 4, Participated: true, Place: 4, TrackPlace: 2},
+		{Event: "Optics", TeamNumber: 5, Participated: true},
+		{Event: "Optics", TeamNumber: 6, Participated: true},
+		{Event: "Optics", TeamNumber: 7},
+		{Event: "Optics", TeamNumber: 8, Participated: true, Place: 5, TrackPlace: 3},
+	},
+}
+
+func TestSuperscore(t *testing.T) {
+	superscored := sciolyff.Superscore(illinoisStates, sciolyff.PlacingOrderEvent)
+	for _, problem := range validator.Validate(superscored) {
+		t.Errorf("invalid superscored results: %s", problem)
+	}
+
+	tournament := superscored.Tournament
+	if tournament.Name != "Illinois State (Superscore)" || len(superscored.Tracks) != 0 {
+		t.Errorf("got tournament %q with tracks %v", tournament.Name, superscored.Tracks)
+	}
+	// Awards are limited to the five schools, and bids go to teams only
+	if tournament.Medals != 5 || tournament.Trophies != 5 || tournament.Bids != 0 || tournament.BidsPerSchool != 0 {
+		t.Errorf("got %d medals, %d trophies, %d bids and %d bids per school", tournament.Medals, tournament.Trophies, tournament.Bids, tournament.BidsPerSchool)
+	}
+
+	// Each school keeps the lowest number of its teams, without a track
+	teams := []string{}
+	for _, team := range superscored.Teams {
+		teams = append(teams, fmt.Sprintf("%d %s%s", team.TeamNumber, team.Name, team.Track))
+	}
+	if got, want := strings.Join(teams, ", "), "1 Troy HS, 3 Lincoln HS (Portland), 4 Lincoln HS (Salem), 5 Oak, 7 Maple"; got != want {
+		t.Errorf("got teams %s, want %s", got, want)
+	}
+
+	// Each school takes its best placing, which is placed again among the
+	// schools. Maple's participation and no-show are kept as they were.
+	placings := []string{}
+	for _, p := range superscored.Placings {
+		placings = append(placings, fmt.Sprintf("%s %d: %d %t %d %t", p.Event, p.TeamNumber, p.Place, p.Tie, p.TrackPlace, p.Participated))
+	}
+	want := []string{
+		"Codebusters 1: 1 false 0 true", "Codebusters 3: 2 false 0 true", "Codebusters 4: 3 false 0 true",
+		"Codebusters 5: 4 false 0 true", "Codebusters 7: 0 false 0 true",
+		"Optics 1: 1 false 0 true", "Optics 3: 2 false 0 true", "Optics 4: 3 false 0 true",
+		"Optics 5: 0 false 0 true", "Optics 7: 0 false 0 false",
+	}
+	if !slices.Equal(placings, want) {
+		t.Errorf("got placings\n%s\nwant\n%s", strings.Join(placings, "\n"), strings.Join(want, "\n"))
+	}
+
+	encoded := strings.Builder{}
+	if err := output.Encode(&encoded, superscored, output.FormatYAML, "test", nil); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(encoded.String(), "track") {
+		t.Errorf("superscored teams were written with tracks:\n%s", encoded.String())
+	}
+}
@@ -0,0 +1,83 @@
+package sciolyff
+
+import (
+	"errors"
+
+	"github.com/Nydauron/avocado2sciolyff/prompts"
+	sciolyff_models "github.com/Nydauron/avocado2sciolyff/sciolyff/models"
+)
+
+// Fills in the tournament details that do not depend on the results, either
+// from the preset options or by prompting. Questions left unanswered do not
+// stop the others from being asked, and are all returned together.
+func promptTournament(p prompts.Prompter, opts Options) (sciolyff_models.TournamentMetadata, error) {
+	tournament := sciolyff_models.TournamentMetadata{}
+	unanswered := []error{}
+	for _, field := range []struct {
+		value  *string
+		preset *string
+		prompt func() (string, error)
+	}{
+		{&tournament.Name, opts.Name, func() (string, error) {
+			return prompts.TextPrompt(p, "name", "Tournament name:", opts.Inferred.Name, false)
+		}},
+		{&tournament.ShortName, opts.ShortName, func() (string, error) {
+			return prompts.TextPrompt(p, "short name", "Tournament nickname/short name:", "", true)
+		}},
+		{&tournament.Location, opts.Location, func() (string, error) {
+			return prompts.TextPrompt(p, "location", "Tournament location (host building/campus):", "", false)
+		}},
+		{&tournament.Level, opts.Level, func() (string, error) { return prompts.TournamentLevelPrompt(p, opts.Inferred.Level) }},
+		{&tournament.State, opts.State, func() (string, error) {
+			return prompts.StatePrompt(p, opts.Inferred.State, tournament.Level == "Nationals")
+		}},
+		{&tournament.Division, opts.Division, func() (string, error) { return prompts.TournamentDivisionPrompt(p, opts.Inferred.Division) }},
+	} {
+		value, err := presetOrPrompt(field.preset, field.prompt)
+		if err := collectUnanswered(err, &unanswered); err != nil {
+			return tournament, err
+		}
+		*field.value = value
+	}
+	date, err := presetOrPrompt(opts.Date, func() (string, error) { return prompts.TournamentDatePrompt(p, opts.Inferred.Date) })
+	if err := collectUnanswered(err, &unanswered); err != nil {
+		return tournament, err
+	}
+	tournament.Date = date
+	// The end date defaults to the start date, so it cannot be asked without it
+	if date != "" {
+		endDate, err := presetOrPrompt(opts.EndDate, func() (string, error) { return prompts.TournamentEndDatePrompt(p, date) })
+		if err := collectUnanswered(err, &unanswered); err != nil {
+			return tournament, err
+		}
+		if endDate != "" {
+			if err := prompts.ValidateDateRange(date, endDate); err != nil {
+				return tournament, err
+			}
+			// Single day tournaments only have a date
+			if endDate != date {
+				tournament.StartDate = date
+				tournament.EndDate = endDate
+			}
+		}
+	}
+	if opts.AwardsDate != nil {
+		tournament.AwardsDate = *opts.AwardsDate
+	}
+	if opts.TestDate != nil {
+		tournament.TestDate = *opts.TestDate
+	}
+
+	// The year in the tournament name is a calendar year, so the season of
+	// the date is a better guess at the rules year whenever there is a date
+	defaultYear := opts.Inferred.Year
+	if seasonYear, ok := prompts.SeasonRulesYear(date); ok {
+		defaultYear = seasonYear
+	}
+	year, err := presetOrPrompt(opts.Year, func() (int, error) { return prompts.RulesYearPrompt(p, defaultYear) })
+	if err := collectUnanswered(err, &unanswered); err != nil {
+		return tournament, err
+	}
+	tournament.Year = year
+	return tournament, errors.Join(unanswered...)
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/Nydauron/avocado2sciolyff/output"
+	"github.com/Nydauron/avocado2sciolyff/validator"
+	"github.com/urfave/cli/v2"
+)
+
+// Validates each results file given as an argument, listing every problem
+func validateHandle(cCtx *cli.Context) error {
+	if cCtx.NArg() == 0 {
+		return fmt.Errorf("no files to validate")
+	}
+	invalidFileCount := 0
+	for _, path := range cCtx.Args().Slice() {
+		sciolyffDump, err := output.Load(path)
+		if err != nil {
+			return err
+		}
+		problems := validator.Validate(sciolyffDump)
+		for _, problem := range problems {
+			fmt.Printf("%s: %s\n", path, problem)
+		}
+		if len(problems) > 0 {
+			invalidFileCount++
+		} else {
+			fmt.Printf("%s: valid\n", path)
+		}
+	}
+	if invalidFileCount > 0 {
+		return cli.Exit(fmt.Sprintf("%d of %d files are invalid", invalidFileCount, cCtx.NArg()), 1)
+	}
+	return nil
+}
@@ -0,0 +1,275 @@
+package validator
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/Nydauron/avocado2sciolyff/prompts"
+	"github.com/Nydauron/avocado2sciolyff/sciolyff"
+	sciolyff_models "github.com/Nydauron/avocado2sciolyff/sciolyff/models"
+)
+
+var levels = []string{"Invitational", "Regionals", "States", "Nationals"}
+
+// Problem is a single violation of the sciolyff rules
+type Problem struct {
+	// The offending entry, e.g. `Placings[12] (event Codebusters, team 5)`
+	Entry   string
+	Message string
+}
+
+func (p Problem) String() string {
+	return p.Entry + ": " + p.Message
+}
+
+type problems []Problem
+
+func (ps *problems) add(entry string, format string, args ...any) {
+	*ps = append(*ps, Problem{Entry: entry, Message: fmt.Sprintf(format, args...)})
+}
+
+// Validate checks results against the sciolyff rules and returns every
+// problem found
+func Validate(s sciolyff_models.SciolyFF) []Problem {
+	ps := problems{}
+	validateTournament(&ps, s)
+	validateTracks(&ps, s)
+	validateEvents(&ps, s)
+	validateTeams(&ps, s)
+	validatePlacings(&ps, s)
+	return ps
+}
+
+func validateTournament(ps *problems, s sciolyff_models.SciolyFF) {
+	const entry = "Tournament"
+	t := s.Tournament
+	for _, field := range []struct{ name, value string }{
+		{"name", t.Name},
+		{"location", t.Location},
+		{"level", t.Level},
+		{"division", t.Division},
+		{"date", t.Date},
+	} {
+		if field.value == "" {
+			ps.add(entry, "%s is missing", field.name)
+		}
+	}
+	if t.Level != "" && !slices.Contains(levels, t.Level) {
+		ps.add(entry, "level %q is not one of %v", t.Level, levels)
+	}
+	if t.Division != "" {
+		if division, err := prompts.ParseDivision(t.Division); err != nil || division != t.Division {
+			ps.add(entry, "division %q is not A, B or C", t.Division)
+		}
+	}
+	if t.State == "" && t.Level != "Nationals" {
+		ps.add(entry, "state is missing")
+	} else if t.State != "" {
+		if state, err := prompts.ParseState(t.State); err != nil || state != t.State {
+			ps.add(entry, "state %q is not a state abbreviation", t.State)
+		}
+	}
+	if t.Year <= 0 {
+		ps.add(entry, "year is missing")
+	}
+
+	for _, field := range []struct{ name, value string }{
+		{"date", t.Date},
+		{"start date", t.StartDate},
+		{"end date", t.EndDate},
+		{"awards date", t.AwardsDate},
+		{"test date", t.TestDate},
+	} {
+		if field.value == "" {
+			continue
+		}
+		if _, err := time.Parse(time.DateOnly, field.value); err != nil {
+			ps.add(entry, "%s %q is not a date of the form YYYY-MM-DD", field.name, field.value)
+		}
+	}
+	if (t.StartDate == "") != (t.EndDate == "") {
+		ps.add(entry, "start date and end date must be given together")
+	} else if t.StartDate != "" {
+		if err := prompts.ValidateDateRange(t.StartDate, t.EndDate); err != nil {
+			ps.add(entry, "%v", err)
+		}
+	}
+
+	if !sciolyff.LevelHasBids(t.Level) && (t.Bids != 0 || t.BidsPerSchool != 0) {
+		ps.add(entry, "bids are only given at regionals and states")
+	}
+	if t.BidsPerSchool > t.Bids {
+		ps.add(entry, "bids per school (%d) is more than the bids (%d)", t.BidsPerSchool, t.Bids)
+	}
+	if t.Medals < 0 || t.Trophies < 0 || t.Bids < 0 || t.BidsPerSchool < 0 || t.WorstPlacingsDropped < 0 || t.NOffset < 0 {
+		ps.add(entry, "medals, trophies, bids, worst placings dropped and n offset must not be negative")
+	}
+	if t.WorstPlacingsDropped > 0 && t.WorstPlacingsDropped >= len(s.Events) {
+		ps.add(entry, "worst placings dropped (%d) leaves no events to score out of %d", t.WorstPlacingsDropped, len(s.Events))
+	}
+	if _, err := sciolyff.ParsePerEventN(t.PerEventN); err != nil && t.PerEventN != "" {
+		ps.add(entry, "per-event n: %v", err)
+	}
+}
+
+func validateTracks(ps *problems, s sciolyff_models.SciolyFF) {
+	seen := map[string]int{}
+	for i, track := range s.Tracks {
+		entry := fmt.Sprintf("Tracks[%d] (%s)", i, track.Name)
+		if track.Name == "" {
+			ps.add(entry, "name is missing")
+		} else if j, ok := seen[track.Name]; ok {
+			ps.add(entry, "duplicates the name of Tracks[%d]", j)
+		} else {
+			seen[track.Name] = i
+		}
+		if track.Medals < 0 || track.Trophies < 0 {
+			ps.add(entry, "medals and trophies must not be negative")
+		}
+	}
+}
+
+func validateEvents(ps *problems, s sciolyff_models.SciolyFF) {
+	seen := map[string]int{}
+	for i, event := range s.Events {
+		entry := fmt.Sprintf("Events[%d] (%s)", i, event.Name)
+		if event.Name == "" {
+			ps.add(entry, "name is missing")
+		} else if j, ok := seen[event.Name]; ok {
+			ps.add(entry, "duplicates the name of Events[%d]", j)
+		} else {
+			seen[event.Name] = i
+		}
+		if event.IsTrial && event.TrialedNormalEvent {
+			ps.add(entry, "cannot be both a trial event and a trialed event")
+		}
+		if event.ScoringObjective != "" && event.ScoringObjective != "high" && event.ScoringObjective != "low" {
+			ps.add(entry, "scoring %q is not high or low", event.ScoringObjective)
+		}
+	}
+}
+
+func validateTeams(ps *problems, s sciolyff_models.SciolyFF) {
+	trackNames := map[string]struct{}{}
+	for _, track := range s.Tracks {
+		trackNames[track.Name] = struct{}{}
+	}
+	seen := map[uint]int{}
+	for i, team := range s.Teams {
+		entry := fmt.Sprintf("Teams[%d] (team %d)", i, team.TeamNumber)
+		if team.TeamNumber == 0 {
+			ps.add(entry, "number is missing")
+		} else if j, ok := seen[team.TeamNumber]; ok {
+			ps.add(entry, "duplicates the number of Teams[%d]", j)
+		} else {
+			seen[team.TeamNumber] = i
+		}
+		if team.Name == "" {
+			ps.add(entry, "school is missing")
+		}
+		if team.HasEmptyTrack() {
+			ps.add(entry, "track is given but empty")
+		} else if len(s.Tracks) == 0 && team.Track != "" {
+			ps.add(entry, "track %q is given but the tournament has no tracks", team.Track)
+		} else if _, ok := trackNames[team.Track]; len(s.Tracks) > 0 && !ok {
+			ps.add(entry, "track %q is not one of the tracks", team.Track)
+		}
+	}
+}
+
+func validatePlacings(ps *problems, s sciolyff_models.SciolyFF) {
+	events := map[string]struct{}{}
+	for _, event := range s.Events {
+		events[event.Name] = struct{}{}
+	}
+	teamTracks := map[uint]string{}
+	teamCountPerTrack := map[string]uint{}
+	for _, team := range s.Teams {
+		teamTracks[team.TeamNumber] = team.Track
+		teamCountPerTrack[team.Track]++
+	}
+	teamCount := uint(len(s.Teams))
+
+	type eventTeam struct {
+		event string
+		team  uint
+	}
+	seen := map[eventTeam]int{}
+	// Indices of the placings with each place, by event
+	placingsByPlaceByEvent := map[string]map[uint][]int{}
+	for i, p := range s.Placings {
+		entry := fmt.Sprintf("Placings[%d] (event %s, team %d)", i, p.Event, p.TeamNumber)
+		if _, ok := events[p.Event]; !ok {
+			ps.add(entry, "event %q is not one of the events", p.Event)
+		}
+		track, isTeamKnown := teamTracks[p.TeamNumber]
+		if !isTeamKnown {
+			ps.add(entry, "team %d is not one of the teams", p.TeamNumber)
+		}
+		key := eventTeam{p.Event, p.TeamNumber}
+		if j, ok := seen[key]; ok {
+			ps.add(entry, "duplicates Placings[%d]", j)
+		} else {
+			seen[key] = i
+		}
+
+		if p.Place != 0 {
+			switch {
+			case !p.Participated:
+				ps.add(entry, "has a place but did not participate")
+			case p.EventDQ:
+				ps.add(entry, "has a place but was disqualified")
+			case p.Unknown:
+				ps.add(entry, "has a place but is marked unknown")
+			}
+			if p.Place > teamCount {
+				ps.add(entry, "place %d is more than the %d teams", p.Place, teamCount)
+			}
+			if placingsByPlaceByEvent[p.Event] == nil {
+				placingsByPlaceByEvent[p.Event] = map[uint][]int{}
+			}
+			placingsByPlaceByEvent[p.Event][p.Place] = append(placingsByPlaceByEvent[p.Event][p.Place], i)
+		} else if p.Tie {
+			ps.add(entry, "is marked as a tie but has no place")
+		}
+
+		if p.TrackPlace != 0 {
+			switch {
+			case len(s.Tracks) == 0:
+				ps.add(entry, "has a track place but the tournament has no tracks")
+			case p.Place == 0:
+				ps.add(entry, "has a track place but no place")
+			case p.TrackPlace > p.Place:
+				ps.add(entry, "track place %d is worse than place %d", p.TrackPlace, p.Place)
+			case isTeamKnown && p.TrackPlace > teamCountPerTrack[track]:
+				ps.add(entry, "track place %d is more than the %d teams in track %s", p.TrackPlace, teamCountPerTrack[track], track)
+			}
+		}
+	}
+
+	for i, team := range s.Teams {
+		for _, event := range s.Events {
+			if _, ok := seen[eventTeam{event.Name, team.TeamNumber}]; !ok {
+				ps.add(fmt.Sprintf("Teams[%d] (team %d)", i, team.TeamNumber), "has no placing in event %s", event.Name)
+			}
+		}
+	}
+
+	for _, event := range s.Events {
+		placingsByPlace := placingsByPlaceByEvent[event.Name]
+		for _, place := range slices.Sorted(maps.Keys(placingsByPlace)) {
+			indices := placingsByPlace[place]
+			for _, i := range indices {
+				p := s.Placings[i]
+				entry := fmt.Sprintf("Placings[%d] (event %s, team %d)", i, p.Event, p.TeamNumber)
+				if len(indices) > 1 && !p.Tie {
+					ps.add(entry, "shares place %d with %d other teams but is not marked as a tie", place, len(indices)-1)
+				} else if len(indices) == 1 && p.Tie {
+					ps.add(entry, "is marked as a tie but no other team has place %d", place)
+				}
+			}
+		}
+	}
+}
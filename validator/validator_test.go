@@ -0,0 +1,103 @@
+package validator_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Nydauron/avocado2sciolyff/output"
+	"github.com/Nydauron/avocado2sciolyff/validator"
+)
+
+// A valid state tournament with two tracks and a tie in Codebusters
+const valid = `Tournament:
+  name: Illinois State
+  location: UIUC
+  level: States
+  state: IL
+  division: C
+  year: 2024
+  date: 2024-04-20
+  bids: 2
+Tracks:
+  - name: JV
+  - name: V
+Events:
+  - name: Codebusters
+  - name: Optics
+Teams:
+  - number: 1
+    school: Troy HS
+    track: V
+  - number: 2
+    school: Lincoln HS
+    track: V
+  - number: 3
+    school: Oak HS
+    track: JV
+Placings:
+  - {event: Codebusters, team: 1, place: 1, track place: 1, tie: true}
+  - {event: Codebusters, team: 2, place: 1, track place: 1, tie: true}
+  - {event: Codebusters, team: 3, place: 3, track place: 1}
+  - {event: Optics, team: 1, place: 2, track place: 2}
+  - {event: Optics, team: 2, place: 1, track place: 1}
+  - {event: Optics, team: 3}
+`
+
+func TestValidate(t *testing.T) {
+	// Each case edits the valid results by replacing the first occurrence of
+	// old with new
+	tests := []struct {
+		name     string
+		old, new string
+		want     []string
+	}{
+		{"valid", "", "", nil},
+		{"missing location", "  location: UIUC\n", "", []string{"Tournament: location is missing"}},
+		{"nationals without a state", "  level: States\n  state: IL\n", "  level: Nationals\n", []string{"Tournament: bids are only given at regionals and states"}},
+		{"invalid date", "date: 2024-04-20", "date: 4/20/24", []string{`Tournament: date "4/20/24" is not a date of the form YYYY-MM-DD`}},
+		{"duplicate team number", "number: 3", "number: 1", []string{
+			"Teams[2] (team 1): duplicates the number of Teams[0]",
+			"Placings[2] (event Codebusters, team 3): team 3 is not one of the teams",
+			"Placings[3] (event Optics, team 1): track place 2 is more than the 1 teams in track JV",
+			"Placings[5] (event Optics, team 3): team 3 is not one of the teams",
+		}},
+		{"unknown event", "{event: Optics, team: 3}", "{event: Wind Power, team: 3}", []string{
+			`Placings[5] (event Wind Power, team 3): event "Wind Power" is not one of the events`,
+			"Teams[2] (team 3): has no placing in event Optics",
+		}},
+		{"empty track", "track: JV", `track: ""`, []string{
+			"Teams[2] (team 3): track is given but empty",
+		}},
+		{"unknown track", "track: JV", "track: Varsity", []string{
+			`Teams[2] (team 3): track "Varsity" is not one of the tracks`,
+		}},
+		{"place without participating", "team: 3, place: 3,", "team: 3, participated: false, place: 3,", []string{
+			"Placings[2] (event Codebusters, team 3): has a place but did not participate",
+		}},
+		{"unmarked tie", "team: 2, place: 1, track place: 1, tie: true", "team: 2, place: 1, track place: 1", []string{
+			"Placings[1] (event Codebusters, team 2): shares place 1 with 1 other teams but is not marked as a tie",
+		}},
+		{"tie without a shared place", "place: 3, track place: 1}", "place: 3, track place: 1, tie: true}", []string{
+			"Placings[2] (event Codebusters, team 3): is marked as a tie but no other team has place 3",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := strings.Replace(valid, tt.old, tt.new, 1)
+			if doc == valid && tt.old != "" {
+				t.Fatalf("%q is not in the valid results", tt.old)
+			}
+			s, err := output.Decode(strings.NewReader(doc), output.FormatYAML)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := []string{}
+			for _, problem := range validator.Validate(s) {
+				got = append(got, problem.String())
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("got problems:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
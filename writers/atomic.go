@@ -0,0 +1,125 @@
+package writers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Writes to a temporary file next to the target, which only replaces the
+// target once closed. Until then, the target is left untouched, so a failed
+// write never leaves a partial file behind.
+type AtomicFileWriteCloser struct {
+	path      string
+	noClobber bool
+	temp      *os.File
+}
+
+// Creates a new `AtomicFileWriteCloser` for the file at path. The temporary
+// file is only created once written to. With noClobber, an existing file at
+// path is never replaced.
+func NewAtomicWriteCloser(path string, noClobber bool) *AtomicFileWriteCloser {
+	return &AtomicFileWriteCloser{path: path, noClobber: noClobber}
+}
+
+func (f *AtomicFileWriteCloser) Write(p []byte) (int, error) {
+	if f.temp == nil {
+		if err := f.checkClobber(); err != nil {
+			return 0, err
+		}
+		var err error
+		f.temp, err = os.CreateTemp(filepath.Dir(f.path), "."+filepath.Base(f.path)+".tmp-*")
+		if err != nil {
+			return 0, err
+		}
+	}
+	return f.temp.Write(p)
+}
+
+// Replaces the target with everything written so far, keeping the mode of the
+// file it replaces. Nothing is created if nothing was written.
+func (f *AtomicFileWriteCloser) Close() error {
+	if f.temp == nil {
+		return nil
+	}
+	tempPath := f.temp.Name()
+	err := f.temp.Close()
+	f.temp = nil
+	mode := fs.FileMode(0644)
+	if info, statErr := os.Stat(f.path); statErr == nil {
+		mode = info.Mode().Perm()
+	}
+	if err == nil {
+		err = os.Chmod(tempPath, mode)
+	}
+	if err == nil && f.noClobber {
+		// Linking fails if the target exists, so a file created since it was
+		// last checked is never replaced. Not every file system supports
+		// links, so exclusively creating the target is the fallback.
+		if err = os.Link(tempPath, f.path); err != nil && !errors.Is(err, fs.ErrExist) {
+			err = copyExclusive(tempPath, f.path, mode)
+		}
+		if errors.Is(err, fs.ErrExist) {
+			err = fmt.Errorf("refusing to overwrite %s: %w", f.path, fs.ErrExist)
+		}
+		if removeErr := os.Remove(tempPath); err == nil {
+			err = removeErr
+		}
+		return err
+	}
+	if err == nil {
+		err = os.Rename(tempPath, f.path)
+	}
+	if err != nil {
+		os.Remove(tempPath)
+	}
+	return err
+}
+
+// Discards everything written so far, leaving the target untouched
+func (f *AtomicFileWriteCloser) Abort() error {
+	if f.temp == nil {
+		return nil
+	}
+	tempPath := f.temp.Name()
+	f.temp.Close()
+	f.temp = nil
+	return os.Remove(tempPath)
+}
+
+// Fails early if the target already exists. The target is checked again when
+// it is replaced.
+func (f *AtomicFileWriteCloser) checkClobber() error {
+	if !f.noClobber {
+		return nil
+	}
+	if _, err := os.Stat(f.path); err == nil {
+		return fmt.Errorf("refusing to overwrite %s: %w", f.path, fs.ErrExist)
+	}
+	return nil
+}
+
+// Copies the file at src to a new file at dst, failing if dst already exists.
+// A failed copy removes the partial dst.
+func copyExclusive(src, dst string, mode fs.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, mode)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(out, in)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(dst)
+	}
+	return err
+}
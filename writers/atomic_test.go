@@ -0,0 +1,114 @@
+package writers_test
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Nydauron/avocado2sciolyff/writers"
+)
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(contents)
+}
+
+func TestAtomicWriteCloser(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results.yaml")
+	w := writers.NewAtomicWriteCloser(path, false)
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("closing without writing created the file: %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte("old"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	w = writers.NewAtomicWriteCloser(path, false)
+	if _, err := io.WriteString(w, "new"); err != nil {
+		t.Fatal(err)
+	}
+	if got := readFile(t, path); got != "old" {
+		t.Errorf("the file was replaced before closing: %q", got)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got := readFile(t, path); got != "new" {
+		t.Errorf("got %q after closing", got)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("the replaced file lost its mode: %v, %v", info.Mode(), err)
+	}
+
+	w = writers.NewAtomicWriteCloser(path, false)
+	if _, err := io.WriteString(w, "partial"); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Abort(); err != nil {
+		t.Fatal(err)
+	}
+	if got := readFile(t, path); got != "new" {
+		t.Errorf("got %q after aborting", got)
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(path)); len(entries) != 1 {
+		t.Errorf("temporary files were left behind: %v", entries)
+	}
+}
+
+func TestAtomicWriteCloserNoClobber(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "results.yaml")
+	w := writers.NewAtomicWriteCloser(path, true)
+	if _, err := io.WriteString(w, "first"); err != nil {
+		t.Fatal(err)
+	}
+	// Another conversion writes the file in the meantime
+	if err := os.WriteFile(path, []byte("second"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); !errors.Is(err, fs.ErrExist) {
+		t.Errorf("got %v for a file created while writing", err)
+	}
+	if got := readFile(t, path); got != "second" {
+		t.Errorf("the file created while writing was replaced with %q", got)
+	}
+
+	if _, err := io.WriteString(writers.NewAtomicWriteCloser(path, true), "third"); !errors.Is(err, fs.ErrExist) {
+		t.Errorf("got %v for writing to an existing file", err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("temporary files were left behind: %v", entries)
+	}
+}
+
+func TestLazyWriteCloser(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results.yaml")
+	w := writers.NewLazyWriteCloser(func() (io.WriteCloser, error) { return os.Create(path) })
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("closing without writing created the file: %v", err)
+	}
+
+	w = writers.NewLazyWriteCloser(func() (io.WriteCloser, error) { return os.Create(path) })
+	if _, err := io.WriteString(w, "results"); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got := readFile(t, path); got != "results" {
+		t.Errorf("got %q", got)
+	}
+}
@@ -0,0 +1,31 @@
+package writers
+
+import (
+	"io"
+)
+
+// AbortWriteCloser is a writer whose output can either be kept by closing it
+// or discarded by aborting it
+type AbortWriteCloser interface {
+	io.WriteCloser
+	Abort() error
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
+
+// Output already written cannot be taken back
+func (nopWriteCloser) Abort() error {
+	return nil
+}
+
+// Wraps a writer that should be left open (e.g. stdout) with a no-op `Close`
+// and `Abort`
+func NopWriteCloser(w io.Writer) AbortWriteCloser {
+	return nopWriteCloser{w}
+}
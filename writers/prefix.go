@@ -0,0 +1,53 @@
+package writers
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// Writes every line with a prefix, e.g. to tell apart the output of
+// conversions running side by side. Each line is written whole in a single
+// write, so lines from several `PrefixWriter`s sharing a writer never mix.
+type PrefixWriter struct {
+	w      io.Writer
+	prefix string
+	mu     sync.Mutex
+	// Start of a line that has not been ended yet
+	partial []byte
+}
+
+// Creates a new `PrefixWriter` writing to w
+func NewPrefixWriter(w io.Writer, prefix string) *PrefixWriter {
+	return &PrefixWriter{w: w, prefix: prefix}
+}
+
+func (pw *PrefixWriter) Write(p []byte) (int, error) {
+	pw.mu.Lock()
+	defer pw.mu.Unlock()
+	pw.partial = append(pw.partial, p...)
+	for {
+		end := bytes.IndexByte(pw.partial, '\n')
+		if end == -1 {
+			return len(p), nil
+		}
+		line := append([]byte(pw.prefix), pw.partial[:end+1]...)
+		pw.partial = pw.partial[end+1:]
+		if _, err := pw.w.Write(line); err != nil {
+			return len(p), err
+		}
+	}
+}
+
+// Writes the last line if it was never ended
+func (pw *PrefixWriter) Flush() error {
+	pw.mu.Lock()
+	defer pw.mu.Unlock()
+	if len(pw.partial) == 0 {
+		return nil
+	}
+	line := append([]byte(pw.prefix), pw.partial...)
+	pw.partial = nil
+	_, err := pw.w.Write(append(line, '\n'))
+	return err
+}